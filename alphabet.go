@@ -6,10 +6,112 @@ package bits
 
 import "strings"
 
+/**
+  An Alphabet is the set of characters which can appear in the words of a
+  trie, together with the mapping between the characters and the codes written
+  in the encoded trie. Every Trie and FrozenTrie holds its own Alphabet, so
+  tries with different alphabets can be used in the same process.
+*/
+type Alphabet struct {
+	characters    string
+	mapCharToUint map[string]uint
+	mapUintToChar map[uint]string
+	dataBits      uint
+}
+
+/**
+  Create an alphabet from the given characters. The code of each character is
+  its position in the string. Note that the space " " must be included, it is
+  used as the letter of the root node.
+*/
+func CreateAlphabet(characters string) *Alphabet {
+	c2ui := getCharToUintMap(characters)
+	return &Alphabet{
+		characters:    characters,
+		mapCharToUint: c2ui,
+		mapUintToChar: getUintToCharMap(c2ui),
+		dataBits:      getDataBits(characters),
+	}
+}
+
+/**
+  Returns the characters of the alphabet.
+*/
+func (a *Alphabet) GetCharacters() string {
+	return a.characters
+}
+
+/**
+  Returns how many bits are used to encode one node, including the "final"
+  indicator.
+*/
+func (a *Alphabet) GetDataBits() uint {
+	return a.dataBits
+}
+
+// encode returns the code of the given letter.
+func (a *Alphabet) encode(letter string) (uint, bool) {
+	code, ok := a.mapCharToUint[letter]
+	return code, ok
+}
+
+// decode returns the letter of the given code.
+func (a *Alphabet) decode(code uint) (string, bool) {
+	letter, ok := a.mapUintToChar[code]
+	return letter, ok
+}
+
+/**
+  Compares two words in the order of the alphabet, the order the words are
+  returned by FrozenTrie.Iter and must be inserted in by DAWGBuilder. Returns
+  -1 if x comes before y, 0 if they are equal and +1 if x comes after y.
+  Letters which are not in the alphabet come after all the others, in the
+  order of their code points.
+*/
+func (a *Alphabet) Compare(x, y string) int {
+	rx, ry := []rune(x), []rune(y)
+	for i := 0; i < len(rx) && i < len(ry); i++ {
+		if rx[i] == ry[i] {
+			continue
+		}
+		cx, okx := a.encode(string(rx[i]))
+		cy, oky := a.encode(string(ry[i]))
+		switch {
+		case okx && oky:
+			if cx < cy {
+				return -1
+			}
+			return 1
+		case okx != oky:
+			if okx {
+				return -1
+			}
+			return 1
+		case rx[i] < ry[i]:
+			return -1
+		default:
+			return 1
+		}
+	}
+	switch {
+	case len(rx) < len(ry):
+		return -1
+	case len(rx) > len(ry):
+		return 1
+	}
+	return 0
+}
+
 //var allowedCharacters = "abcdeghijklmnoprstuvyāīūṁṃŋṇṅñṭḍḷ…'’° -"
 var allowedCharacters = "abcdefghijklmnopqrstuvwxyz "
-var mapCharToUint = getCharToUintMap(allowedCharacters)
-var mapUintToChar = getUintToCharMap(mapCharToUint)
+
+/**
+ * The alphabet used by tries which are not given one explicitly. The globals
+ * below are kept for backward compatibility and always mirror it.
+ */
+var defaultAlphabet = CreateAlphabet(allowedCharacters)
+var mapCharToUint = defaultAlphabet.mapCharToUint
+var mapUintToChar = defaultAlphabet.mapUintToChar
 
 /**
  * Write the data for each node, call getDataBits() to calculate how many bits
@@ -17,13 +119,25 @@ var mapUintToChar = getUintToCharMap(mapCharToUint)
  * 1 bit stores the "final" indicator. The other bits store one of the
  * characters of the alphabet.
  */
-var dataBits = getDataBits(allowedCharacters)
+var dataBits = defaultAlphabet.dataBits
 
+/**
+  Set the default alphabet, which is used by Trie.Init and FrozenTrie.Init.
+  Tries already initialized keep the alphabet they were created with.
+*/
 func SetAllowedCharacters(alphabet string) {
+	defaultAlphabet = CreateAlphabet(alphabet)
 	allowedCharacters = alphabet
-	mapCharToUint = getCharToUintMap(alphabet)
-	mapUintToChar = getUintToCharMap(mapCharToUint)
-	dataBits = getDataBits(alphabet)
+	mapCharToUint = defaultAlphabet.mapCharToUint
+	mapUintToChar = defaultAlphabet.mapUintToChar
+	dataBits = defaultAlphabet.dataBits
+}
+
+/**
+  Returns the default alphabet.
+*/
+func DefaultAlphabet() *Alphabet {
+	return defaultAlphabet
 }
 
 func getCharToUintMap(alphabet string) map[string]uint {
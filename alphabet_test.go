@@ -39,3 +39,62 @@ func TestAlphabet(t *testing.T) {
 		t.Log(dataBits)
 	}
 }
+
+func TestAlphabetPerTrie(t *testing.T) {
+	english := CreateAlphabet("abcdefghijklmnopqrstuvwxyz ")
+	pali := CreateAlphabet("abcdeghijklmnoprstuvyāīūṁṃŋṇṅñṭḍḷ…'’° -")
+	if pali.GetDataBits() != 7 {
+		t.Error("pali.GetDataBits() != 7")
+	}
+
+	te := Trie{}
+	te.InitWithAlphabet(english)
+	insertNotInAlphabeticalOrder(&te)
+	teData := te.Encode()
+	rd := CreateRankDirectory(teData, te.GetNodeCount()*2+1, L1, L2)
+
+	tp := Trie{}
+	tp.InitWithAlphabet(pali)
+	tp.Insert("sacca")
+	tp.Insert("dhammaṃ")
+	tp.Insert("buddho")
+	tpData := tp.Encode()
+	rdp := CreateRankDirectory(tpData, tp.GetNodeCount()*2+1, L1, L2)
+
+	fe := FrozenTrie{}
+	fe.InitWithAlphabet(teData, rd.GetData(), te.GetNodeCount(), english)
+	fp := FrozenTrie{}
+	fp.InitWithAlphabet(tpData, rdp.GetData(), tp.GetNodeCount(), pali)
+
+	if fe.Lookup("apple") != true {
+		t.Error("apple")
+	}
+	if fp.Lookup("dhammaṃ") != true {
+		t.Error("dhammaṃ")
+	}
+	if fp.Lookup("dhamma") != false {
+		t.Error("dhamma")
+	}
+	if dataBits != 6 {
+		t.Error("default alphabet changed")
+	}
+}
+
+func TestAlphabetCompare(t *testing.T) {
+	a := CreateAlphabet("zyx ")
+	for _, c := range []struct {
+		x, y     string
+		expected int
+	}{
+		{"z", "y", -1},
+		{"yx", "yz", 1},
+		{"y", "yx", -1},
+		{"xy", "xy", 0},
+		{"x", "a", -1},
+		{"a", "b", -1},
+	} {
+		if result := a.Compare(c.x, c.y); result != c.expected {
+			t.Error("Compare", c.x, c.y, result)
+		}
+	}
+}
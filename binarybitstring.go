@@ -0,0 +1,115 @@
+package bits
+
+/**
+  A BitVector supports reading or counting a number of bits from an arbitrary
+  position. BitString stores the bits in BASE-64 characters, BinaryBitString
+  stores them in 64-bit words.
+*/
+type BitVector interface {
+	Get(p, n uint) uint
+	Count(p, n uint) uint
+	Rank(x uint) uint
+	Length() uint
+}
+
+/**
+  The storage of the encoded bits of a trie.
+*/
+type Storage int
+
+const (
+	// BASE-64 characters, as used by the JavaScript version
+	StorageBase64 Storage = iota
+	// raw 64-bit words, smaller and faster to read in Go
+	StorageBinary
+)
+
+/**
+  Given a slice of 64-bit words, the BinaryBitString supports reading or
+  counting a number of bits from an arbitrary position, the same as BitString
+  does for BASE-64 strings. Bit 0 is the most significant bit of the first
+  word.
+*/
+type BinaryBitString struct {
+	words  []uint64
+	length uint
+}
+
+func (bs *BinaryBitString) Init(words []uint64) {
+	bs.words = words
+	bs.length = uint(len(words)) * 64
+}
+
+/**
+  Returns the internal slice of words
+*/
+func (bs *BinaryBitString) GetWords() []uint64 {
+	return bs.words
+}
+
+/**
+  Returns the number of bits, including the padding of the last word
+*/
+func (bs *BinaryBitString) Length() uint {
+	return bs.length
+}
+
+/**
+  Returns a decimal number, consisting of a certain number, n, of bits
+  starting at a certain position, p.
+*/
+func (bs *BinaryBitString) Get(p, n uint) uint {
+	if n == 0 {
+		return 0
+	}
+
+	idx := p / 64
+	offset := p % 64
+
+	// case 1: bits lie within the given word
+	if offset+n <= 64 {
+		return uint((bs.words[idx] << offset) >> (64 - n))
+	}
+
+	// case 2: bits span two words
+	l := 64 - offset
+	high := (bs.words[idx] << offset) >> offset
+	low := bs.words[idx+1] >> (64 - (n - l))
+	return uint(high<<(n-l) | low)
+}
+
+/**
+  Counts the number of bits set to 1 starting at position p and
+  ending at position p + n
+*/
+func (bs *BinaryBitString) Count(p, n uint) uint {
+	var count uint = 0
+	for n > 0 {
+		offset := p % 64
+		m := 64 - offset
+		if m > n {
+			m = n
+		}
+		word := (bs.words[p/64] << offset) >> (64 - m)
+		count += popCount(word)
+		p += m
+		n -= m
+	}
+
+	return count
+}
+
+/**
+  Returns the number of bits set to 1 up to and including position x.
+*/
+func (bs *BinaryBitString) Rank(x uint) uint {
+	return bs.Count(0, x+1)
+}
+
+func popCount(word uint64) uint {
+	var count uint = 0
+	for ; word != 0; word >>= 8 {
+		count += BitsInByte[word&0xff]
+	}
+	return count
+}
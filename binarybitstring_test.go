@@ -0,0 +1,75 @@
+package bits
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+)
+
+func TestBinaryBitString(t *testing.T) {
+	bw := BitWriter{}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 500; i++ {
+		bw.Write(uint(r.Intn(2)), 1)
+	}
+
+	bs := BitString{}
+	bs.Init(bw.GetData())
+	bbs := BinaryBitString{}
+	bbs.Init(bw.GetWords())
+
+	var p, n uint
+	for p = 0; p < 450; p += 7 {
+		for n = 1; n <= 40; n += 3 {
+			if bs.Get(p, n) != bbs.Get(p, n) {
+				t.Error("Get", p, n, bs.Get(p, n), bbs.Get(p, n))
+			}
+			if bs.Count(p, n) != bbs.Count(p, n) {
+				t.Error("Count", p, n, bs.Count(p, n), bbs.Count(p, n))
+			}
+		}
+		if bs.Rank(p) != bbs.Rank(p) {
+			t.Error("Rank", p, bs.Rank(p), bbs.Rank(p))
+		}
+	}
+}
+
+func TestBinaryStorage(t *testing.T) {
+	te := Trie{}
+	te.Init()
+	insertNotInAlphabeticalOrder(&te)
+	teData := te.EncodeBinary()
+	rd := CreateBinaryRankDirectory(teData, te.GetNodeCount()*2+1, L1, L2)
+
+	ft := FrozenTrie{}
+	ft.InitBinary(teData, rd.GetWords(), te.GetNodeCount(), DefaultAlphabet())
+	if ft.Lookup("alphapha") != true {
+		t.Error("alphapha")
+	}
+	if ft.Lookup("alphaph") != false {
+		t.Error("alphaph")
+	}
+
+	var base64Buf, binaryBuf bytes.Buffer
+	if err := te.Save(&base64Buf); err != nil {
+		t.Fatal(err)
+	}
+	te.SetStorage(StorageBinary)
+	if err := te.Save(&binaryBuf); err != nil {
+		t.Fatal(err)
+	}
+	if binaryBuf.Len() >= base64Buf.Len() {
+		t.Error("binary container is not smaller", binaryBuf.Len(), base64Buf.Len())
+	}
+
+	ft2, err := Load(&binaryBuf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ft2.Lookup("quiz") != true {
+		t.Error("quiz")
+	}
+	if ft2.Lookup("quize") != false {
+		t.Error("quize")
+	}
+}
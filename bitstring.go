@@ -40,6 +40,13 @@ func (bs *BitString) GetData() string {
 	return bs.base64DataString
 }
 
+/**
+  Returns the number of bits, including the padding of the last character
+*/
+func (bs *BitString) Length() uint {
+	return bs.length
+}
+
 /**
   Returns a decimal number, consisting of a certain number, n, of bits
   starting at a certain position, p.
@@ -91,6 +98,10 @@ func (bs *BitString) Count(p, n uint) uint {
 		n -= 8
 	}
 
+	// p may be the end of the data
+	if n == 0 {
+		return count
+	}
 	return count + BitsInByte[bs.Get(p, n)]
 }
 
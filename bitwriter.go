@@ -5,10 +5,12 @@ import "strings"
 /**
   The BitWriter will create a stream of bytes, letting you write a certain
   number of bits at a time. This is part of the encoder, so it is not
-  optimized for memory or speed.
+  optimized for speed. The bits are packed into 64-bit words, most
+  significant bit first.
 */
 type BitWriter struct {
-	bits []uint
+	words  []uint64
+	length uint
 }
 
 /**
@@ -24,23 +26,30 @@ func (bw *BitWriter) Write(data, numBits uint) {
 	// of -1, loop condition is still true...
 	for i := numBits; i > 0; i-- {
 		j := i - 1
+		if bw.length%64 == 0 {
+			bw.words = append(bw.words, 0)
+		}
 		if (data & (1 << j)) != 0 {
-			bw.bits = append(bw.bits, 1)
-		} else {
-			bw.bits = append(bw.bits, 0)
+			bw.words[bw.length/64] |= 1 << (63 - bw.length%64)
 		}
+		bw.length++
 	}
 }
 
+// get returns the j'th bit written.
+func (bw *BitWriter) get(j uint) uint {
+	return uint(bw.words[j/64]>>(63-j%64)) & 1
+}
+
 /**
   Get the bitstring represented as a javascript string of bytes
 */
 func (bw *BitWriter) GetData() string {
 	var chars []string
-	var b, i uint = 0, 0
+	var b, i, j uint = 0, 0, 0
 
-	for j := 0; j < len(bw.bits); j++ {
-		b = (b << 1) | bw.bits[j]
+	for j = 0; j < bw.length; j++ {
+		b = (b << 1) | bw.get(j)
 		i += 1
 		if i == W {
 			chars = append(chars, CHR(b))
@@ -61,10 +70,10 @@ func (bw *BitWriter) GetData() string {
 */
 func (bw *BitWriter) GetDebugString(group uint) string {
 	var chars []string
-	var i uint = 0
+	var i, j uint = 0, 0
 
-	for j := 0; j < len(bw.bits); j++ {
-		if bw.bits[j] == 1 {
+	for j = 0; j < bw.length; j++ {
+		if bw.get(j) == 1 {
 			chars = append(chars, "1")
 		} else {
 			chars = append(chars, "0")
@@ -78,3 +87,13 @@ func (bw *BitWriter) GetDebugString(group uint) string {
 
 	return strings.Join(chars, "")
 }
+
+/**
+  Get the bitstring packed into 64-bit words, for use with BinaryBitString.
+  The last word is padded with 0 bits.
+*/
+func (bw *BitWriter) GetWords() []uint64 {
+	words := make([]uint64, len(bw.words))
+	copy(words, bw.words)
+	return words
+}
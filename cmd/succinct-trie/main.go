@@ -0,0 +1,370 @@
+// Command succinct-trie builds and queries succinct tries stored in the JSON
+// format of the examples.
+//
+// Usage:
+//
+//	succinct-trie build [-alphabet chars | -order codepoint|frequency] [-normalize names] [-o trie.json] [wordlist]
+//	succinct-trie lookup [-t trie.json] [-alphabet chars] word...
+//	succinct-trie suggest [-t trie.json] [-alphabet chars] [-limit n] prefix
+//	succinct-trie stats [-t trie.json] [-alphabet chars]
+//	succinct-trie dump [-t trie.json] [-alphabet chars]
+//	succinct-trie serve [-t trie.json] [-alphabet chars] [-addr :8080] [-reload 5s]
+//
+// The word list has one word per line and is read from the standard input if
+// no file is given. Without -alphabet, build uses the letters of the words,
+// in the order given by -order. -normalize is a comma-separated list of the
+// normalizers applied to the words, see package normalize.
+// Trie files written by the examples have no alphabet, -alphabet gives it to
+// the other subcommands, otherwise the default alphabet is used. For files
+// which have one, -alphabet must be the same.
+//
+// serve answers the HTTP requests of package httptrie, and reads the trie
+// file again when it changes.
+//
+// The exit code is 0 on success, 1 if lookup does not find a word or suggest
+// finds none, and 2 on errors.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	bits "github.com/siongui/go-succinct-data-structure-trie"
+	"github.com/siongui/go-succinct-data-structure-trie/httptrie"
+	_ "github.com/siongui/go-succinct-data-structure-trie/normalize"
+)
+
+// exit codes
+const (
+	exitOK       = 0
+	exitNotFound = 1
+	exitError    = 2
+)
+
+// errNotFound makes a subcommand exit with exitNotFound, and errUsage with
+// exitError, without printing them: the flag package reports bad flags.
+var (
+	errNotFound = errors.New("not found")
+	errUsage    = errors.New("usage")
+)
+
+type command struct {
+	name  string
+	usage string
+	run   func(args []string, stdin io.Reader, stdout, stderr io.Writer) error
+}
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{"build", "[-alphabet chars | -order codepoint|frequency] [-normalize names] [-o trie.json] [wordlist]", runBuild},
+		{"lookup", "[-t trie.json] [-alphabet chars] word...", runLookup},
+		{"suggest", "[-t trie.json] [-alphabet chars] [-limit n] prefix", runSuggest},
+		{"stats", "[-t trie.json] [-alphabet chars]", runStats},
+		{"dump", "[-t trie.json] [-alphabet chars]", runDump},
+		{"serve", "[-t trie.json] [-alphabet chars] [-addr :8080] [-reload 5s]", runServe},
+	}
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs the subcommand named by args[0] and returns the exit code.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return exitError
+	}
+	for _, c := range commands {
+		if c.name != args[0] {
+			continue
+		}
+		err := c.run(args[1:], stdin, stdout, stderr)
+		switch {
+		case err == nil:
+			return exitOK
+		case err == errNotFound:
+			return exitNotFound
+		case err == errUsage:
+			return exitError
+		}
+		fmt.Fprintln(stderr, "succinct-trie "+c.name+":", err)
+		return exitError
+	}
+	usage(stderr)
+	return exitError
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage:")
+	for _, c := range commands {
+		fmt.Fprintln(w, "  succinct-trie", c.name, c.usage)
+	}
+}
+
+// newFlagSet returns the flags of a subcommand, with -alphabet and, unless
+// building, -t.
+func newFlagSet(name string, stderr io.Writer, trieFile, alphabet *string) *flag.FlagSet {
+	fs := flag.NewFlagSet("succinct-trie "+name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(alphabet, "alphabet", "", "the characters of the alphabet, including the space")
+	if trieFile != nil {
+		fs.StringVar(trieFile, "t", "trie.json", "the trie file")
+	}
+	return fs
+}
+
+// parseFlags parses the flags. The flag set reports the errors.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	return nil
+}
+
+func runBuild(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	var characters, output, order, normalizers string
+	fs := newFlagSet("build", stderr, nil, &characters)
+	fs.StringVar(&output, "o", "trie.json", "the trie file to write")
+	fs.StringVar(&order, "order", "codepoint", "the order of the letters of the words without -alphabet: codepoint or frequency")
+	fs.StringVar(&normalizers, "normalize", "", "the comma-separated normalizers of the words, for example nfc,casefold")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		return errors.New("more than one word list")
+	}
+
+	r := stdin
+	if fs.NArg() == 1 && fs.Arg(0) != "-" {
+		file, err := os.Open(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		r = file
+	}
+	words, err := readWords(r)
+	if err != nil {
+		return err
+	}
+
+	te := bits.Trie{}
+	switch {
+	case characters != "":
+		// the space is the letter of the root
+		if !strings.Contains(characters, " ") {
+			return errors.New("the alphabet has no space")
+		}
+		te.InitWithAlphabet(bits.CreateAlphabet(characters))
+	case order == "codepoint":
+		te.InitWithDerivedAlphabet(bits.OrderByCodePoint)
+	case order == "frequency":
+		te.InitWithDerivedAlphabet(bits.OrderByFrequency)
+	default:
+		return errors.New("unknown order " + order)
+	}
+	if normalizers != "" {
+		if err := te.SetNormalizers(strings.Split(normalizers, ",")...); err != nil {
+			return fmt.Errorf("%v: %s", err, normalizers)
+		}
+	}
+	for _, word := range words {
+		if err := te.Insert(word); err != nil {
+			return err
+		}
+	}
+
+	b, err := json.Marshal(bits.CreateTrieData(&te))
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(output, b, 0644); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "%d words, %d nodes written to %s\n", len(words), te.GetNodeCount(), output)
+	return nil
+}
+
+// readWords returns the non-empty lines.
+func readWords(r io.Reader) ([]string, error) {
+	var words []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		word := strings.TrimRight(scanner.Text(), "\r")
+		if word != "" {
+			words = append(words, word)
+		}
+	}
+	return words, scanner.Err()
+}
+
+// loadTrie reads the trie file, using the alphabet given by -alphabet if the
+// file has none. An -alphabet which is not the one of the file is an error.
+func loadTrie(trieFile, characters string) (*bits.FrozenTrie, error) {
+	b, err := ioutil.ReadFile(trieFile)
+	if err != nil {
+		return nil, err
+	}
+	var td bits.TrieData
+	if err := json.Unmarshal(b, &td); err != nil {
+		return nil, fmt.Errorf("%s: %v", trieFile, err)
+	}
+
+	alphabet := bits.DefaultAlphabet()
+	if characters != "" {
+		if td.Alphabet != "" && td.Alphabet != characters {
+			return nil, fmt.Errorf("%s: the file has another alphabet", trieFile)
+		}
+		alphabet = bits.CreateAlphabet(characters)
+	}
+	ft, err := td.CreateFrozenTrie(alphabet)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", trieFile, err)
+	}
+	return ft, nil
+}
+
+func runLookup(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	var trieFile, characters string
+	fs := newFlagSet("lookup", stderr, &trieFile, &characters)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return errors.New("no word to look up")
+	}
+	ft, err := loadTrie(trieFile, characters)
+	if err != nil {
+		return err
+	}
+
+	var result error
+	for _, word := range fs.Args() {
+		found := ft.Lookup(word)
+		fmt.Fprintf(stdout, "%s\t%v\n", word, found)
+		if !found {
+			result = errNotFound
+		}
+	}
+	return result
+}
+
+func runSuggest(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	var trieFile, characters string
+	var limit int
+	fs := newFlagSet("suggest", stderr, &trieFile, &characters)
+	fs.IntVar(&limit, "limit", 10, "the maximum number of words")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("expected one prefix")
+	}
+	ft, err := loadTrie(trieFile, characters)
+	if err != nil {
+		return err
+	}
+
+	words := ft.GetSuggestedWords(fs.Arg(0), limit)
+	if len(words) > limit {
+		words = words[:limit]
+	}
+	for _, word := range words {
+		fmt.Fprintln(stdout, word)
+	}
+	if len(words) == 0 {
+		return errNotFound
+	}
+	return nil
+}
+
+func runStats(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	var trieFile, characters string
+	fs := newFlagSet("stats", stderr, &trieFile, &characters)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return errors.New("unexpected arguments")
+	}
+	ft, err := loadTrie(trieFile, characters)
+	if err != nil {
+		return err
+	}
+
+	alphabet := ft.GetAlphabet()
+	dataBits := alphabet.GetDataBits()
+	nodeCount := ft.GetNodeCount()
+	fmt.Fprintf(stdout, "words\t%d\n", ft.GetWordCount())
+	fmt.Fprintf(stdout, "nodes\t%d\n", nodeCount)
+	fmt.Fprintf(stdout, "alphabet\t%q\n", alphabet.GetCharacters())
+	fmt.Fprintf(stdout, "data bits per node\t%d\n", dataBits)
+	fmt.Fprintf(stdout, "encoded bits\t%d\n", nodeCount*2+1+nodeCount*dataBits)
+	return nil
+}
+
+func runDump(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	var trieFile, characters string
+	fs := newFlagSet("dump", stderr, &trieFile, &characters)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return errors.New("unexpected arguments")
+	}
+	ft, err := loadTrie(trieFile, characters)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(stdout)
+	it := ft.Iter()
+	for word, ok := it.Next(); ok; word, ok = it.Next() {
+		fmt.Fprintln(w, word)
+	}
+	return w.Flush()
+}
+
+func runServe(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	var trieFile, characters, addr string
+	var interval time.Duration
+	fs := newFlagSet("serve", stderr, &trieFile, &characters)
+	fs.StringVar(&addr, "addr", ":8080", "the address to listen on")
+	fs.DurationVar(&interval, "reload", 5*time.Second, "how often to check the trie file for changes, 0 to never")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return errors.New("unexpected arguments")
+	}
+
+	alphabet := bits.DefaultAlphabet()
+	if characters != "" {
+		alphabet = bits.CreateAlphabet(characters)
+	}
+	h, err := httptrie.CreateHandler(trieFile, alphabet)
+	if err != nil {
+		return err
+	}
+	if interval > 0 {
+		go h.Watch(interval, nil, func(err error) {
+			fmt.Fprintln(stderr, "succinct-trie serve: reload:", err)
+		})
+	}
+
+	fmt.Fprintln(stdout, "serving", trieFile, "on", addr)
+	return http.ListenAndServe(addr, h)
+}
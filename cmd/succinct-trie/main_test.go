@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "succinct-trie")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	trieFile := filepath.Join(dir, "trie.json")
+
+	runCommand := func(stdin string, args ...string) (int, string) {
+		var stdout, stderr bytes.Buffer
+		code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+		return code, stdout.String()
+	}
+
+	if code, _ := runCommand("dhamma\r\nbuddha\n\nsaṅgha\ndhammo\n", "build", "-o", trieFile); code != exitOK {
+		t.Fatal("build", code)
+	}
+
+	if code, out := runCommand("", "lookup", "-t", trieFile, "dhamma", "saṅgha"); code != exitOK || out != "dhamma\ttrue\nsaṅgha\ttrue\n" {
+		t.Error("lookup", code, out)
+	}
+	if code, _ := runCommand("", "lookup", "-t", trieFile, "dhamma", "sangha"); code != exitNotFound {
+		t.Error("lookup not found", code)
+	}
+	if code, out := runCommand("", "suggest", "-t", trieFile, "-limit", "1", "dhamm"); code != exitOK || out != "dhamma\n" {
+		t.Error("suggest", code, out)
+	}
+	if code, _ := runCommand("", "suggest", "-t", trieFile, "x"); code != exitNotFound {
+		t.Error("suggest not found", code)
+	}
+	if code, out := runCommand("", "dump", "-t", trieFile); code != exitOK || out != "buddha\ndhamma\ndhammo\nsaṅgha\n" {
+		t.Error("dump", code, out)
+	}
+	if code, out := runCommand("", "stats", "-t", trieFile); code != exitOK || !strings.HasPrefix(out, "words\t4\n") {
+		t.Error("stats", code, out)
+	}
+
+	// alphabet flag, illegal characters and errors
+	if code, _ := runCommand("abc\n", "build", "-alphabet", "ab ", "-o", trieFile); code != exitError {
+		t.Error("build illegal character", code)
+	}
+	if code, _ := runCommand("", "build", "-alphabet", "abc", "-o", trieFile); code != exitError {
+		t.Error("build alphabet without space", code)
+	}
+	if code, _ := runCommand("ab\nb\n", "build", "-order", "frequency", "-o", trieFile); code != exitOK {
+		t.Error("build by frequency", code)
+	}
+	if code, out := runCommand("", "dump", "-t", trieFile, "-alphabet", "ba "); code != exitOK || out != "b\nab\n" {
+		t.Error("dump with the alphabet of the file", code, out)
+	}
+	if code, _ := runCommand("", "dump", "-t", trieFile, "-alphabet", " ab"); code != exitError {
+		t.Error("dump with another alphabet", code)
+	}
+	if code, out := runCommand("", "dump", "-t", trieFile); code != exitOK || out != "b\nab\n" {
+		t.Error("dump by frequency", code, out)
+	}
+	if code, _ := runCommand("Dhamma\n", "build", "-normalize", "nfc,casefold", "-o", trieFile); code != exitOK {
+		t.Error("build normalized", code)
+	}
+	if code, _ := runCommand("", "lookup", "-t", trieFile, "DHAMMA"); code != exitOK {
+		t.Error("lookup normalized", code)
+	}
+	if code, _ := runCommand("a\n", "build", "-normalize", "nfx", "-o", trieFile); code != exitError {
+		t.Error("build unknown normalizer", code)
+	}
+	if code, _ := runCommand("a\n", "build", "-order", "size", "-o", trieFile); code != exitError {
+		t.Error("build unknown order", code)
+	}
+	if code, _ := runCommand("", "lookup", "-t", filepath.Join(dir, "missing.json"), "a"); code != exitError {
+		t.Error("lookup missing file", code)
+	}
+	if code, _ := runCommand("", "serve", "-t", filepath.Join(dir, "missing.json")); code != exitError {
+		t.Error("serve missing file", code)
+	}
+	if code, _ := runCommand("", "lookup", "-x"); code != exitError {
+		t.Error("bad flag", code)
+	}
+	if code, _ := runCommand("", "unknown"); code != exitError {
+		t.Error("unknown subcommand", code)
+	}
+}
@@ -0,0 +1,491 @@
+package bits
+
+/**
+ * The container is a self-describing binary format which bundles everything
+ * needed to decode a trie: the encoded trie, the rank directory, the node
+ * count, the alphabet, the sizes of the directory blocks, how the bits are
+ * stored, and if any, the values and weights attached to the words, the
+ * failure links for substring search, the reversed trie for suffix search
+ * and the names of the normalizers of the words. Whether the children of
+ * every node are in the order of the alphabet is recorded too, as it is not
+ * true for tries saved by old versions.
+ *
+ * Layout:
+ *
+ *   magic "SDST" | version | sections... | end tag | CRC-32 (IEEE)
+ *
+ * Every section is written as tag, length, payload. Integers are unsigned
+ * varints and strings are prefixed by their length. Bits are written as a
+ * BASE-64 string, or as a count of 64-bit words followed by the words in
+ * little-endian order, depending on the storage section. The checksum covers
+ * all the bytes before it and is stored in little-endian order.
+ */
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+)
+
+const containerMagic = "SDST"
+const containerVersion = 1
+
+// section tags of the container
+const (
+	sectionEnd = iota
+	sectionAlphabet
+	sectionDirectorySizes
+	sectionNodeCount
+	sectionTrieData
+	sectionDirectoryData
+	sectionStorage
+	sectionValues
+	sectionWeights
+	sectionFailureLinks
+	sectionSuffixIndex
+	sectionNormalizers
+	sectionSortedChildren
+)
+
+// errors returned by Load
+var (
+	ErrInvalidFormat      = errors.New("bits: invalid trie container")
+	ErrUnsupportedVersion = errors.New("bits: unsupported trie container version")
+	ErrChecksumMismatch   = errors.New("bits: trie container checksum mismatch")
+	ErrDataMismatch       = errors.New("bits: trie container fields do not match")
+)
+
+/**
+  Encode the trie and write it, together with its rank directory and
+  alphabet, to w. The global L1 and L2 are used as the sizes of the directory
+  blocks. Use Load to read it back. ErrNoSpace is returned if the alphabet
+  has no space.
+*/
+func (t *Trie) Save(w io.Writer) error {
+	if err := t.checkAlphabet(); err != nil {
+		return err
+	}
+	data, directoryData := t.appendTrieBits()
+
+	var buf []byte
+	buf = append(buf, containerMagic...)
+	buf = appendUvarint(buf, containerVersion)
+	buf = appendSection(buf, sectionAlphabet, appendString(nil, t.GetAlphabet().GetCharacters()))
+	sizes := appendUvarint(nil, uint64(L1))
+	sizes = appendUvarint(sizes, uint64(L2))
+	buf = appendSection(buf, sectionDirectorySizes, sizes)
+	buf = appendSection(buf, sectionNodeCount, appendUvarint(nil, uint64(t.GetNodeCount())))
+	buf = appendSection(buf, sectionStorage, appendUvarint(nil, uint64(t.storage)))
+	buf = appendSection(buf, sectionTrieData, data)
+	buf = appendSection(buf, sectionDirectoryData, directoryData)
+	buf = appendSection(buf, sectionSortedChildren, appendUvarint(nil, 1))
+	if t.hasValues {
+		bw, width := t.writeValues()
+		buf = appendSection(buf, sectionValues, appendBits(appendUvarint(nil, uint64(width)), bw, t.storage))
+	}
+	if t.hasWeights {
+		weights, maxWeights, width := t.writeWeights()
+		payload := appendUvarint(nil, uint64(width))
+		payload = appendBits(payload, weights, t.storage)
+		payload = appendBits(payload, maxWeights, t.storage)
+		buf = appendSection(buf, sectionWeights, payload)
+	}
+	if t.buildFailureLinks {
+		fail, out, width, maxDepth := t.writeFailureLinks()
+		payload := appendUvarint(nil, uint64(width))
+		payload = appendUvarint(payload, uint64(maxDepth))
+		payload = appendBits(payload, fail, t.storage)
+		payload = appendBits(payload, out, t.storage)
+		buf = appendSection(buf, sectionFailureLinks, payload)
+	}
+	if t.buildSuffixIndex {
+		rt := t.buildReversed()
+		rdata, rdirectoryData := rt.appendTrieBits()
+		ids, width := rt.writeValues()
+		payload := appendUvarint(nil, uint64(rt.GetNodeCount()))
+		payload = appendString(payload, string(rdata))
+		payload = appendString(payload, string(rdirectoryData))
+		payload = appendUvarint(payload, uint64(width))
+		payload = appendBits(payload, ids, t.storage)
+		buf = appendSection(buf, sectionSuffixIndex, payload)
+	}
+	if len(t.normalizers) > 0 {
+		payload := appendUvarint(nil, uint64(len(t.normalizers)))
+		for _, name := range t.normalizers {
+			payload = appendString(payload, name)
+		}
+		buf = appendSection(buf, sectionNormalizers, payload)
+	}
+	buf = appendUvarint(buf, sectionEnd)
+	buf = appendChecksum(buf)
+
+	_, err := w.Write(buf)
+	return err
+}
+
+// appendTrieBits encodes the trie and its rank directory in the storage of
+// the trie, as the payloads of the trie data and directory data sections.
+func (t *Trie) appendTrieBits() ([]byte, []byte) {
+	bits := t.encodeBits()
+	numBits := t.GetNodeCount()*2 + 1
+
+	switch t.storage {
+	case StorageBinary:
+		words := bits.GetWords()
+		rd := CreateBinaryRankDirectoryWithSelect(words, numBits, L1, L2, t.selectSampleRate)
+		return appendWords(nil, words), appendWords(nil, rd.GetWords())
+	default:
+		s := bits.GetData()
+		rd := CreateRankDirectoryWithSelect(s, numBits, L1, L2, t.selectSampleRate)
+		return appendString(nil, s), appendString(nil, rd.GetData())
+	}
+}
+
+/**
+  Read a trie written by Trie.Save and return the FrozenTrie for it. Blobs
+  which are truncated, corrupted, written by a newer version or whose fields
+  do not match each other are rejected. A *DataError is returned if the
+  fields match but do not encode a valid trie, and ErrUnknownNormalizer if
+  the trie has a normalizer which is not registered.
+*/
+func Load(r io.Reader) (*FrozenTrie, error) {
+	buf, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(buf) < len(containerMagic)+4 || string(buf[:len(containerMagic)]) != containerMagic {
+		return nil, ErrInvalidFormat
+	}
+	body := buf[:len(buf)-4]
+	if binary.LittleEndian.Uint32(buf[len(buf)-4:]) != crc32.ChecksumIEEE(body) {
+		return nil, ErrChecksumMismatch
+	}
+
+	cr := containerReader{buf: body[len(containerMagic):]}
+	version := cr.uvarint()
+	if cr.err != nil {
+		return nil, cr.err
+	}
+	if version != containerVersion {
+		return nil, ErrUnsupportedVersion
+	}
+
+	var characters string
+	var normalizers []string
+	var data, directoryData, values, weights, failureLinks, suffixIndex []byte
+	var l1Size, l2Size, nodeCount uint
+	var sortedChildren bool
+	storage := StorageBase64
+	seen := map[uint64]bool{}
+	for cr.err == nil {
+		tag := cr.uvarint()
+		if tag == sectionEnd {
+			break
+		}
+		if seen[tag] {
+			return nil, ErrInvalidFormat
+		}
+		seen[tag] = true
+
+		payload := cr.bytes()
+		sr := containerReader{buf: payload}
+		switch tag {
+		case sectionAlphabet:
+			characters = sr.string()
+		case sectionDirectorySizes:
+			l1Size = uint(sr.uvarint())
+			l2Size = uint(sr.uvarint())
+		case sectionNodeCount:
+			nodeCount = uint(sr.uvarint())
+		case sectionStorage:
+			storage = Storage(sr.uvarint())
+		case sectionTrieData:
+			data, sr.buf = payload, nil
+		case sectionDirectoryData:
+			directoryData, sr.buf = payload, nil
+		case sectionValues:
+			values, sr.buf = payload, nil
+		case sectionWeights:
+			weights, sr.buf = payload, nil
+		case sectionFailureLinks:
+			failureLinks, sr.buf = payload, nil
+		case sectionSuffixIndex:
+			suffixIndex, sr.buf = payload, nil
+		case sectionNormalizers:
+			n := sr.uvarint()
+			for ; n > 0 && sr.err == nil; n-- {
+				normalizers = append(normalizers, sr.string())
+			}
+		case sectionSortedChildren:
+			switch sr.uvarint() {
+			case 0:
+			case 1:
+				sortedChildren = true
+			default:
+				return nil, ErrInvalidFormat
+			}
+		default:
+			return nil, ErrInvalidFormat
+		}
+		if sr.err != nil || len(sr.buf) != 0 {
+			return nil, ErrInvalidFormat
+		}
+	}
+	if cr.err != nil || len(cr.buf) != 0 {
+		return nil, ErrInvalidFormat
+	}
+	for tag := sectionAlphabet; tag <= sectionDirectoryData; tag++ {
+		if !seen[uint64(tag)] {
+			return nil, ErrInvalidFormat
+		}
+	}
+
+	alphabet := CreateAlphabet(characters)
+	if checkTrieParams(nodeCount, l1Size, l2Size, alphabet) != nil {
+		return nil, ErrDataMismatch
+	}
+	if _, ok := alphabet.encode(" "); !ok {
+		return nil, ErrDataMismatch
+	}
+
+	bits, directory, err := readContainerBits(storage, data, directoryData,
+		nodeCount, alphabet.GetDataBits(), l1Size, l2Size)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := createFrozenTrie(bits, directory, nodeCount, l1Size, l2Size, alphabet, sortedChildren)
+	if err != nil {
+		return nil, err
+	}
+
+	if values != nil {
+		vr := containerReader{buf: values}
+		width := uint(vr.uvarint())
+		vbits := vr.bits(storage)
+		if vr.err != nil || len(vr.buf) != 0 || width > 64 {
+			return nil, ErrInvalidFormat
+		}
+		f.values.init(vbits, width, f.GetWordCount())
+		if !f.values.fits() {
+			return nil, ErrDataMismatch
+		}
+	}
+
+	if weights != nil {
+		wr := containerReader{buf: weights}
+		width := uint(wr.uvarint())
+		wbits := wr.bits(storage)
+		mbits := wr.bits(storage)
+		if wr.err != nil || len(wr.buf) != 0 || width > 64 {
+			return nil, ErrInvalidFormat
+		}
+		f.weights.init(wbits, width, f.GetWordCount())
+		f.maxWeights.init(mbits, width, f.nodeCount)
+		if !f.weights.fits() || !f.maxWeights.fits() {
+			return nil, ErrDataMismatch
+		}
+	}
+
+	if failureLinks != nil {
+		fr := containerReader{buf: failureLinks}
+		width := uint(fr.uvarint())
+		maxDepth := uint(fr.uvarint())
+		fail := fr.bits(storage)
+		out := fr.bits(storage)
+		if fr.err != nil || len(fr.buf) != 0 || width > 64 || maxDepth >= nodeCount {
+			return nil, ErrInvalidFormat
+		}
+		f.setFailureLinks(fail, out, width, maxDepth)
+		if !f.checkFailureLinks() {
+			return nil, ErrDataMismatch
+		}
+	}
+
+	if err := f.SetNormalizers(normalizers...); err != nil {
+		return nil, err
+	}
+
+	if suffixIndex != nil {
+		sr := containerReader{buf: suffixIndex}
+		rnodeCount := uint(sr.uvarint())
+		rdata := sr.bytes()
+		rdirectoryData := sr.bytes()
+		width := uint(sr.uvarint())
+		ids := sr.bits(storage)
+		if sr.err != nil || len(sr.buf) != 0 || width > 64 || rnodeCount == 0 {
+			return nil, ErrInvalidFormat
+		}
+		rbits, rdirectory, err := readContainerBits(storage, rdata, rdirectoryData,
+			rnodeCount, alphabet.GetDataBits(), l1Size, l2Size)
+		if err != nil {
+			return nil, err
+		}
+		rt, err := createFrozenTrie(rbits, rdirectory, rnodeCount, l1Size, l2Size, alphabet, sortedChildren)
+		if err != nil {
+			return nil, err
+		}
+		rt.values.init(ids, width, rt.GetWordCount())
+		f.suffixes = rt
+		if !f.checkSuffixIndex() {
+			return nil, ErrDataMismatch
+		}
+	}
+	return f, nil
+}
+
+// readContainerBits decodes the trie data and the rank directory of a
+// container, and makes sure they have the length of nodeCount nodes of
+// dataBits bits and that the directory is the one of the data.
+func readContainerBits(storage Storage, data, directoryData []byte, nodeCount, dataBits, l1Size, l2Size uint) (BitVector, BitVector, error) {
+	var bits, directory BitVector
+	var reason string
+	numBits := nodeCount*2 + 1
+	totalBits := numBits + nodeCount*dataBits
+
+	switch storage {
+	case StorageBase64:
+		dr := containerReader{buf: data}
+		s := dr.string()
+		ddr := containerReader{buf: directoryData}
+		ds := ddr.string()
+		if dr.err != nil || ddr.err != nil || len(dr.buf) != 0 || len(ddr.buf) != 0 {
+			return nil, nil, ErrInvalidFormat
+		}
+		if uint(len(s))*W < nodeCount {
+			return nil, nil, ErrDataMismatch
+		}
+		bits, directory, reason = checkBase64Bits(s, ds, numBits, totalBits, l1Size, l2Size)
+
+	case StorageBinary:
+		dr := containerReader{buf: data}
+		words := dr.words()
+		ddr := containerReader{buf: directoryData}
+		dwords := ddr.words()
+		if dr.err != nil || ddr.err != nil || len(dr.buf) != 0 || len(ddr.buf) != 0 {
+			return nil, nil, ErrInvalidFormat
+		}
+		if uint(len(words))*64 < nodeCount {
+			return nil, nil, ErrDataMismatch
+		}
+		bits, directory, reason = checkBinaryBits(words, dwords, numBits, totalBits, l1Size, l2Size)
+
+	default:
+		return nil, nil, ErrInvalidFormat
+	}
+
+	if reason != "" {
+		return nil, nil, ErrDataMismatch
+	}
+	return bits, directory, nil
+}
+
+func appendChecksum(buf []byte) []byte {
+	var sum [4]byte
+	binary.LittleEndian.PutUint32(sum[:], crc32.ChecksumIEEE(buf))
+	return append(buf, sum[:]...)
+}
+
+func appendUvarint(buf []byte, v uint64) []byte {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tmp[:], v)
+	return append(buf, tmp[:n]...)
+}
+
+func appendString(buf []byte, s string) []byte {
+	buf = appendUvarint(buf, uint64(len(s)))
+	return append(buf, s...)
+}
+
+func appendWords(buf []byte, words []uint64) []byte {
+	buf = appendUvarint(buf, uint64(len(words)))
+	var tmp [8]byte
+	for _, word := range words {
+		binary.LittleEndian.PutUint64(tmp[:], word)
+		buf = append(buf, tmp[:]...)
+	}
+	return buf
+}
+
+// appendBits appends the bits of the writer in the given storage.
+func appendBits(buf []byte, bw *BitWriter, storage Storage) []byte {
+	if storage == StorageBinary {
+		return appendWords(buf, bw.GetWords())
+	}
+	return appendString(buf, bw.GetData())
+}
+
+func appendSection(buf []byte, tag uint64, payload []byte) []byte {
+	buf = appendUvarint(buf, tag)
+	buf = appendUvarint(buf, uint64(len(payload)))
+	return append(buf, payload...)
+}
+
+// containerReader reads the values written by the append functions. The
+// first error is kept in err and all later reads return zero values.
+type containerReader struct {
+	buf []byte
+	err error
+}
+
+func (cr *containerReader) uvarint() uint64 {
+	if cr.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(cr.buf)
+	if n <= 0 {
+		cr.err = ErrInvalidFormat
+		return 0
+	}
+	cr.buf = cr.buf[n:]
+	return v
+}
+
+func (cr *containerReader) bytes() []byte {
+	n := cr.uvarint()
+	if cr.err != nil {
+		return nil
+	}
+	if n > uint64(len(cr.buf)) {
+		cr.err = ErrInvalidFormat
+		return nil
+	}
+	b := cr.buf[:n]
+	cr.buf = cr.buf[n:]
+	return b
+}
+
+func (cr *containerReader) string() string {
+	return string(cr.bytes())
+}
+
+func (cr *containerReader) words() []uint64 {
+	n := cr.uvarint()
+	if cr.err != nil {
+		return nil
+	}
+	if n > uint64(len(cr.buf))/8 {
+		cr.err = ErrInvalidFormat
+		return nil
+	}
+	words := make([]uint64, n)
+	for i := range words {
+		words[i] = binary.LittleEndian.Uint64(cr.buf[i*8:])
+	}
+	cr.buf = cr.buf[n*8:]
+	return words
+}
+
+// bits reads the bits written by appendBits.
+func (cr *containerReader) bits(storage Storage) BitVector {
+	if storage == StorageBinary {
+		bits := &BinaryBitString{}
+		bits.Init(cr.words())
+		return bits
+	}
+	bits := &BitString{}
+	bits.Init(cr.string())
+	return bits
+}
@@ -0,0 +1,132 @@
+package bits
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSaveLoad(t *testing.T) {
+	pali := CreateAlphabet("abcdeghijklmnoprstuvyāīūṁṃŋṇṅñṭḍḷ…'’° -")
+	te := Trie{}
+	te.InitWithAlphabet(pali)
+	te.Insert("sacca")
+	te.Insert("ariya")
+	te.Insert("saccavācā")
+	te.Insert("dhammaṃ")
+
+	var buf bytes.Buffer
+	if err := te.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	blob := buf.Bytes()
+
+	ft, err := Load(bytes.NewReader(blob))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ft.GetAlphabet().GetCharacters() != pali.GetCharacters() {
+		t.Error("alphabet not restored")
+	}
+	if ft.GetNodeCount() != te.GetNodeCount() {
+		t.Error("node count not restored")
+	}
+	if ft.Lookup("saccavācā") != true {
+		t.Error("saccavācā")
+	}
+	if ft.Lookup("sacc") != false {
+		t.Error("sacc")
+	}
+
+	corrupted := append([]byte{}, blob...)
+	corrupted[len(corrupted)/2] ^= 0x01
+	if _, err := Load(bytes.NewReader(corrupted)); err != ErrChecksumMismatch {
+		t.Error("corrupted blob: expected ErrChecksumMismatch, got ", err)
+	}
+
+	if _, err := Load(bytes.NewReader(blob[:len(blob)-1])); err == nil {
+		t.Error("truncated blob accepted")
+	}
+
+	if _, err := Load(bytes.NewReader([]byte("not a trie"))); err != ErrInvalidFormat {
+		t.Error("expected ErrInvalidFormat, got ", err)
+	}
+}
+
+func TestLoadMismatch(t *testing.T) {
+	te := Trie{}
+	te.Init()
+	insertNotInAlphabeticalOrder(&te)
+	data := te.Encode()
+	rd := CreateRankDirectory(data, te.GetNodeCount()*2+1, L1, L2)
+
+	// node count does not match the encoded data
+	buf := []byte(containerMagic)
+	buf = appendUvarint(buf, containerVersion)
+	buf = appendSection(buf, sectionAlphabet, appendString(nil, allowedCharacters))
+	buf = appendSection(buf, sectionDirectorySizes, appendUvarint(appendUvarint(nil, uint64(L1)), uint64(L2)))
+	buf = appendSection(buf, sectionNodeCount, appendUvarint(nil, uint64(te.GetNodeCount()+1)))
+	buf = appendSection(buf, sectionTrieData, appendString(nil, data))
+	buf = appendSection(buf, sectionDirectoryData, appendString(nil, rd.GetData()))
+	buf = appendUvarint(buf, sectionEnd)
+	buf = appendChecksum(buf)
+
+	if _, err := Load(bytes.NewReader(buf)); err != ErrDataMismatch {
+		t.Error("expected ErrDataMismatch, got ", err)
+	}
+
+	// unknown version
+	buf = []byte(containerMagic)
+	buf = appendUvarint(buf, containerVersion+1)
+	buf = appendChecksum(buf)
+	if _, err := Load(bytes.NewReader(buf)); err != ErrUnsupportedVersion {
+		t.Error("expected ErrUnsupportedVersion, got ", err)
+	}
+}
+
+func TestLoadHugeNodeCount(t *testing.T) {
+	te := Trie{}
+	te.Init()
+	insertNotInAlphabeticalOrder(&te)
+	data, directoryData := te.appendTrieBits()
+	// a node count for which the length of the data overflows to the
+	// length of data
+	huge := uint64(^uint(0)/8 + 1)
+
+	container := func(nodeCount uint64, suffixIndex []byte) []byte {
+		buf := []byte(containerMagic)
+		buf = appendUvarint(buf, containerVersion)
+		buf = appendSection(buf, sectionAlphabet, appendString(nil, allowedCharacters))
+		buf = appendSection(buf, sectionDirectorySizes, appendUvarint(appendUvarint(nil, uint64(L1)), uint64(L2)))
+		buf = appendSection(buf, sectionNodeCount, appendUvarint(nil, nodeCount))
+		buf = appendSection(buf, sectionTrieData, data)
+		buf = appendSection(buf, sectionDirectoryData, directoryData)
+		if suffixIndex != nil {
+			buf = appendSection(buf, sectionSuffixIndex, suffixIndex)
+		}
+		buf = appendUvarint(buf, sectionEnd)
+		return appendChecksum(buf)
+	}
+
+	nodeCount := uint64(te.GetNodeCount())
+	if _, err := Load(bytes.NewReader(container(huge+nodeCount, nil))); err != ErrDataMismatch {
+		t.Error("expected ErrDataMismatch, got ", err)
+	}
+
+	rt := te.buildReversed()
+	rdata, rdirectoryData := rt.appendTrieBits()
+	ids, width := rt.writeValues()
+	suffixIndex := func(rnodeCount uint64) []byte {
+		payload := appendUvarint(nil, rnodeCount)
+		payload = appendString(payload, string(rdata))
+		payload = appendString(payload, string(rdirectoryData))
+		payload = appendUvarint(payload, uint64(width))
+		return appendBits(payload, ids, StorageBase64)
+	}
+	rnodeCount := uint64(rt.GetNodeCount())
+	if _, err := Load(bytes.NewReader(container(nodeCount, suffixIndex(rnodeCount)))); err != nil {
+		t.Error(err)
+	}
+	if _, err := Load(bytes.NewReader(container(nodeCount, suffixIndex(huge+rnodeCount)))); err != ErrDataMismatch {
+		t.Error("expected ErrDataMismatch, got ", err)
+	}
+}
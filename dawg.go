@@ -0,0 +1,467 @@
+package bits
+
+/**
+ * Directed acyclic word graph (DAWG), the minimal automaton of a set of
+ * words, in which the words share their endings as well as their prefixes.
+ * DAWGBuilder builds it incrementally from words in sorted order (Daciuk et
+ * al., "Incremental Construction of Minimal Acyclic Finite-State Automata"):
+ * only the states on the path of the last word can still change, and they
+ * are replaced by an equivalent registered state, or registered, as soon as
+ * a word leaves that path.
+ *
+ * The states are numbered in breadth-first order from the start state, and
+ * encoded as:
+ *
+ *   edge counts, unary | final bits | edge letters | target width (7 bits) |
+ *   edge targets | word count width (7 bits) | word counts
+ *
+ * The edge counts are written as in the trie: one 1 bit per edge of the
+ * state followed by a 0 bit, so that the rank directory of those bits finds
+ * the edges of a state. The edges of a state are in the order of the
+ * alphabet, every letter takes dataBits-1 bits and every target as many as
+ * the largest state number. The word count of a state is the number of words
+ * starting from it.
+ */
+
+import (
+	"errors"
+	"unicode/utf8"
+)
+
+// errors returned by DAWGBuilder.Insert
+var (
+	ErrWordOrder   = errors.New("bits: words not inserted in the order of the alphabet")
+	ErrDAWGEncoded = errors.New("bits: DAWG already encoded")
+)
+
+const dawgWidthBits = 7
+
+type dawgNode struct {
+	final bool
+	edges []dawgEdge
+	// the number of the state in the register, and then in the encoding
+	id uint
+}
+
+type dawgEdge struct {
+	from *dawgNode
+	code uint
+	to   *dawgNode
+}
+
+/**
+  Builds a DAWG from words inserted in the order of the alphabet, see
+  Alphabet.Compare.
+*/
+type DAWGBuilder struct {
+	alphabet     *Alphabet
+	previousWord []uint
+	hasWords     bool
+	root         *dawgNode
+	// the edges on the path of the previous word, whose states are not
+	// registered yet
+	unchecked []dawgEdge
+	register  map[string]*dawgNode
+
+	encoded bool
+	states  []*dawgNode
+	edges   uint
+}
+
+/**
+  Initialize the builder with the default alphabet.
+*/
+func (b *DAWGBuilder) Init() {
+	b.InitWithAlphabet(defaultAlphabet)
+}
+
+/**
+  Initialize the builder with the given alphabet.
+*/
+func (b *DAWGBuilder) InitWithAlphabet(alphabet *Alphabet) {
+	b.alphabet = alphabet
+	b.previousWord = nil
+	b.hasWords = false
+	b.root = &dawgNode{}
+	b.unchecked = nil
+	b.register = map[string]*dawgNode{}
+	b.encoded = false
+	b.states = nil
+	b.edges = 0
+}
+
+/**
+  Returns the alphabet of the builder
+*/
+func (b *DAWGBuilder) GetAlphabet() *Alphabet {
+	return b.alphabet
+}
+
+/**
+  Inserts a word. The words must be inserted in the order of the alphabet,
+  ErrWordOrder is returned otherwise, and before Encode is called. Inserting
+  the previous word again does nothing. An *IllegalCharacterError is returned
+  if the word has a letter not in the alphabet.
+*/
+func (b *DAWGBuilder) Insert(word string) error {
+	codes := make([]uint, 0, len(word))
+	for _, runeValue := range word {
+		code, ok := b.alphabet.encode(string(runeValue))
+		if !ok {
+			return &IllegalCharacterError{Word: word, Rune: runeValue}
+		}
+		codes = append(codes, code)
+	}
+	if b.encoded {
+		return ErrDAWGEncoded
+	}
+
+	common := 0
+	for common < len(codes) && common < len(b.previousWord) && codes[common] == b.previousWord[common] {
+		common++
+	}
+	if b.hasWords {
+		if common == len(codes) && common == len(b.previousWord) {
+			return nil
+		}
+		if common == len(codes) || (common < len(b.previousWord) && codes[common] < b.previousWord[common]) {
+			return ErrWordOrder
+		}
+	}
+
+	b.minimize(common)
+	node := b.root
+	if len(b.unchecked) > 0 {
+		node = b.unchecked[len(b.unchecked)-1].to
+	}
+	for _, code := range codes[common:] {
+		next := &dawgNode{}
+		node.edges = append(node.edges, dawgEdge{from: node, code: code, to: next})
+		b.unchecked = append(b.unchecked, dawgEdge{from: node, code: code, to: next})
+		node = next
+	}
+	node.final = true
+
+	b.previousWord = codes
+	b.hasWords = true
+	return nil
+}
+
+// minimize replaces the states of the unchecked edges, down to the given
+// depth, by equivalent registered states, or registers them.
+func (b *DAWGBuilder) minimize(depth int) {
+	for i := len(b.unchecked) - 1; i >= depth; i-- {
+		e := b.unchecked[i]
+		key := e.to.signature()
+		if registered, ok := b.register[key]; ok {
+			e.from.edges[len(e.from.edges)-1].to = registered
+		} else {
+			e.to.id = uint(len(b.register))
+			b.register[key] = e.to
+		}
+	}
+	b.unchecked = b.unchecked[:depth]
+}
+
+// signature identifies the state by its final indicator and its edges. Two
+// states are equivalent if they have the same signature, as the targets of
+// their edges are registered.
+func (n *dawgNode) signature() string {
+	var key []byte
+	if n.final {
+		key = append(key, 1)
+	} else {
+		key = append(key, 0)
+	}
+	for _, e := range n.edges {
+		key = appendUvarint(key, uint64(e.code))
+		key = appendUvarint(key, uint64(e.to.id))
+	}
+	return string(key)
+}
+
+// finish minimizes the path of the last word and numbers the states in
+// breadth-first order.
+func (b *DAWGBuilder) finish() {
+	if b.encoded {
+		return
+	}
+	b.minimize(0)
+	b.encoded = true
+
+	seen := map[*dawgNode]bool{b.root: true}
+	b.states = []*dawgNode{b.root}
+	for i := 0; i < len(b.states); i++ {
+		node := b.states[i]
+		node.id = uint(i)
+		b.edges += uint(len(node.edges))
+		for _, e := range node.edges {
+			if !seen[e.to] {
+				seen[e.to] = true
+				b.states = append(b.states, e.to)
+			}
+		}
+	}
+}
+
+/**
+  Encode the DAWG. Returns a string representing the encoded data. No words
+  can be inserted afterwards.
+*/
+func (b *DAWGBuilder) Encode() string {
+	return b.encodeBits().GetData()
+}
+
+func (b *DAWGBuilder) encodeBits() *BitWriter {
+	b.finish()
+
+	bits := &BitWriter{}
+	for _, node := range b.states {
+		for range node.edges {
+			bits.Write(1, 1)
+		}
+		bits.Write(0, 1)
+	}
+	for _, node := range b.states {
+		if node.final {
+			bits.Write(1, 1)
+		} else {
+			bits.Write(0, 1)
+		}
+	}
+	dataBits := b.alphabet.GetDataBits()
+	for _, node := range b.states {
+		for _, e := range node.edges {
+			bits.Write(e.code, dataBits-1)
+		}
+	}
+
+	width := bitWidth(uint64(len(b.states) - 1))
+	bits.Write(width, dawgWidthBits)
+	for _, node := range b.states {
+		for _, e := range node.edges {
+			writeUint64(bits, uint64(e.to.id), width)
+		}
+	}
+
+	counts := make([]uint64, len(b.states))
+	var count func(node *dawgNode) uint64
+	count = func(node *dawgNode) uint64 {
+		if counts[node.id] != 0 {
+			return counts[node.id]
+		}
+		var n uint64 = 0
+		if node.final {
+			n++
+		}
+		for _, e := range node.edges {
+			n += count(e.to)
+		}
+		counts[node.id] = n
+		return n
+	}
+	// the start state has the most words, its width fits all the others
+	width = bitWidth(count(b.root))
+	bits.Write(width, dawgWidthBits)
+	for _, n := range counts {
+		writeUint64(bits, n, width)
+	}
+	return bits
+}
+
+/**
+  Returns the number of states of the encoded DAWG. Encode must be called
+  first.
+*/
+func (b *DAWGBuilder) GetStateCount() uint {
+	return uint(len(b.states))
+}
+
+/**
+  Returns the number of edges of the encoded DAWG. Encode must be called
+  first.
+*/
+func (b *DAWGBuilder) GetEdgeCount() uint {
+	return b.edges
+}
+
+/**
+  The FrozenDAWG is used for looking up words in a DAWG encoded by
+  DAWGBuilder.Encode.
+
+  The rank directory is the one of the first stateCount+edgeCount bits of
+  the data, for example:
+
+    CreateRankDirectory(data, stateCount+edgeCount, L1, L2)
+*/
+type FrozenDAWG struct {
+	data       BitVector
+	directory  RankDirectory
+	alphabet   *Alphabet
+	stateCount uint
+	edgeCount  uint
+	finalStart uint
+	codeStart  uint
+	targets    packedArray
+	counts     packedArray
+}
+
+func (d *FrozenDAWG) Init(data, directoryData string, stateCount, edgeCount uint) {
+	d.InitWithAlphabet(data, directoryData, stateCount, edgeCount, defaultAlphabet)
+}
+
+func (d *FrozenDAWG) InitWithAlphabet(data, directoryData string, stateCount, edgeCount uint, alphabet *Alphabet) {
+	bits := &BitString{}
+	bits.Init(data)
+	directory := &BitString{}
+	directory.Init(directoryData)
+
+	d.data = bits
+	d.alphabet = alphabet
+	d.stateCount = stateCount
+	d.edgeCount = edgeCount
+	d.directory.init(directory, bits, stateCount+edgeCount, L1, L2)
+
+	d.finalStart = stateCount + edgeCount
+	d.codeStart = d.finalStart + stateCount
+	p := d.codeStart + edgeCount*(alphabet.GetDataBits()-1)
+	width := bits.Get(p, dawgWidthBits)
+	p += dawgWidthBits
+	d.targets.init(&offsetBitVector{bits, p}, width, edgeCount)
+	p += width * edgeCount
+	width = bits.Get(p, dawgWidthBits)
+	p += dawgWidthBits
+	d.counts.init(&offsetBitVector{bits, p}, width, stateCount)
+}
+
+/**
+  Returns the number of states of the DAWG.
+*/
+func (d *FrozenDAWG) GetStateCount() uint {
+	return d.stateCount
+}
+
+// edgeRange returns the first edge of the state and the one after its last.
+func (d *FrozenDAWG) edgeRange(state uint) (uint, uint) {
+	var first uint = 0
+	if state > 0 {
+		first = d.directory.Select(0, state) + 1 - state
+	}
+	return first, d.directory.Select(0, state+1) - state
+}
+
+func (d *FrozenDAWG) isFinal(state uint) bool {
+	return d.data.Get(d.finalStart+state, 1) == 1
+}
+
+func (d *FrozenDAWG) getCode(edge uint) uint {
+	dataBits := d.alphabet.GetDataBits()
+	return d.data.Get(d.codeStart+edge*(dataBits-1), dataBits-1)
+}
+
+// next returns the state reached from the given state by the letter, and
+// true if there is one. The edges are searched by binary search.
+func (d *FrozenDAWG) next(state uint, letter string) (uint, bool) {
+	code, ok := d.alphabet.encode(letter)
+	if !ok {
+		return 0, false
+	}
+	low, high := d.edgeRange(state)
+	for low < high {
+		mid := low + (high-low)/2
+		c := d.getCode(mid)
+		if c == code {
+			return uint(d.targets.get(mid)), true
+		}
+		if c < code {
+			low = mid + 1
+		} else {
+			high = mid
+		}
+	}
+	return 0, false
+}
+
+// findState returns the state reached by the letters of word.
+func (d *FrozenDAWG) findState(word string) (uint, bool) {
+	var state uint = 0
+	for i, w := 0, 0; i < len(word); i += w {
+		runeValue, width := utf8.DecodeRuneInString(word[i:])
+		w = width
+		next, ok := d.next(state, string(runeValue))
+		if !ok {
+			return 0, false
+		}
+		state = next
+	}
+	return state, true
+}
+
+/**
+  Look-up a word in the DAWG. Returns true if and only if the word exists
+  in the DAWG.
+*/
+func (d *FrozenDAWG) Lookup(word string) bool {
+	state, ok := d.findState(word)
+	return ok && d.isFinal(state)
+}
+
+/**
+  Returns the number of words in the DAWG.
+*/
+func (d *FrozenDAWG) GetWordCount() uint {
+	if d.stateCount == 0 {
+		return 0
+	}
+	return uint(d.counts.get(0))
+}
+
+/**
+  Returns the number of words in the DAWG starting with prefix.
+*/
+func (d *FrozenDAWG) CountWithPrefix(prefix string) uint {
+	state, ok := d.findState(prefix)
+	if !ok {
+		return 0
+	}
+	return uint(d.counts.get(state))
+}
+
+/**
+  Given a word, returns at most limit words of the DAWG, prefix of which is
+  word, the shorter words first.
+*/
+func (d *FrozenDAWG) GetSuggestedWords(word string, limit int) []string {
+	var result []string
+	if limit <= 0 {
+		return result
+	}
+	state, ok := d.findState(word)
+	if !ok {
+		return result
+	}
+
+	level := []uint{state}
+	prefixLevel := []string{word}
+	for len(level) > 0 {
+		stateNow := level[0]
+		level = level[1:]
+		prefixNow := prefixLevel[0]
+		prefixLevel = prefixLevel[1:]
+
+		if d.isFinal(stateNow) {
+			result = append(result, prefixNow)
+			if len(result) == limit {
+				return result
+			}
+		}
+
+		first, end := d.edgeRange(stateNow)
+		for e := first; e < end; e++ {
+			letter, _ := d.alphabet.decode(d.getCode(e))
+			level = append(level, uint(d.targets.get(e)))
+			prefixLevel = append(prefixLevel, prefixNow+letter)
+		}
+	}
+	return result
+}
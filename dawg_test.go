@@ -0,0 +1,117 @@
+package bits
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func createFrozenDAWG(b *DAWGBuilder) *FrozenDAWG {
+	data := b.Encode()
+	rd := CreateRankDirectory(data, b.GetStateCount()+b.GetEdgeCount(), L1, L2)
+	d := &FrozenDAWG{}
+	d.InitWithAlphabet(data, rd.GetData(), b.GetStateCount(), b.GetEdgeCount(), b.GetAlphabet())
+	return d
+}
+
+func TestDAWG(t *testing.T) {
+	b := DAWGBuilder{}
+	b.Init()
+	for _, word := range []string{"tap", "taps", "top", "tops"} {
+		if err := b.Insert(word); err != nil {
+			t.Fatal(err)
+		}
+	}
+	d := createFrozenDAWG(&b)
+
+	// "ta" and "to" lead to the same state
+	if b.GetStateCount() != 5 || b.GetEdgeCount() != 5 {
+		t.Error("states and edges", b.GetStateCount(), b.GetEdgeCount())
+	}
+	for word, expected := range map[string]bool{"tap": true, "taps": true, "top": true,
+		"tops": true, "ta": false, "to": false, "tip": false, "": false, "tapss": false} {
+		if d.Lookup(word) != expected {
+			t.Error("Lookup", word)
+		}
+	}
+	if d.GetWordCount() != 4 {
+		t.Error("GetWordCount", d.GetWordCount())
+	}
+	if n := d.CountWithPrefix("to"); n != 2 {
+		t.Error("CountWithPrefix to", n)
+	}
+	if n := d.CountWithPrefix("x"); n != 0 {
+		t.Error("CountWithPrefix x", n)
+	}
+	if result := d.GetSuggestedWords("t", 3); !reflect.DeepEqual(result, []string{"tap", "top", "taps"}) {
+		t.Error("GetSuggestedWords", result)
+	}
+
+	if err := b.Insert("toy"); err != ErrDAWGEncoded {
+		t.Error("Insert after Encode", err)
+	}
+}
+
+func TestDAWGPali(t *testing.T) {
+	alphabet := CreateAlphabet("abcdeghijklmnoprstuvyāīūṁṃŋṇṅñṭḍḷ…'’° -")
+	words := []string{"", "buddha", "buddhaṃ", "buddhassa", "buddho", "dhamma",
+		"dhammaṃ", "dhammassa", "dhammo", "saṅgha", "saṅghaṃ", "saṅghassa", "saṅgho"}
+	sort.Slice(words, func(i, j int) bool {
+		return alphabet.Compare(words[i], words[j]) < 0
+	})
+
+	b := DAWGBuilder{}
+	b.InitWithAlphabet(alphabet)
+	te := Trie{}
+	te.InitWithAlphabet(alphabet)
+	for _, word := range words {
+		if err := b.Insert(word); err != nil {
+			t.Fatal(err)
+		}
+		te.Insert(word)
+	}
+	d := createFrozenDAWG(&b)
+
+	if b.GetStateCount() >= te.GetNodeCount() {
+		t.Error("no state shared", b.GetStateCount(), te.GetNodeCount())
+	}
+	for _, word := range words {
+		if !d.Lookup(word) {
+			t.Error("Lookup", word)
+		}
+	}
+	for _, word := range []string{"buddh", "dhammaa", "saṅghass"} {
+		if d.Lookup(word) {
+			t.Error("Lookup", word)
+		}
+	}
+	if d.GetWordCount() != uint(len(words)) {
+		t.Error("GetWordCount", d.GetWordCount())
+	}
+	result := d.GetSuggestedWords("dhamm", 10)
+	sort.Strings(result)
+	if !reflect.DeepEqual(result, []string{"dhamma", "dhammassa", "dhammaṃ", "dhammo"}) {
+		t.Error("GetSuggestedWords", result)
+	}
+}
+
+func TestDAWGInsertOrder(t *testing.T) {
+	b := DAWGBuilder{}
+	b.Init()
+	b.Insert("b")
+	if err := b.Insert("b"); err != nil {
+		t.Error("Insert the same word", err)
+	}
+	if err := b.Insert("ab"); err != ErrWordOrder {
+		t.Error("Insert before", err)
+	}
+	if err := b.Insert(""); err != ErrWordOrder {
+		t.Error("Insert prefix", err)
+	}
+	if _, ok := b.Insert("B").(*IllegalCharacterError); !ok {
+		t.Error("Insert illegal character")
+	}
+	if err := b.Insert("ba"); err != nil {
+		t.Error("Insert after", err)
+	}
+}
@@ -0,0 +1,68 @@
+package bits
+
+/**
+ * Alphabets derived from the inserted words. Instead of being given an
+ * alphabet, the trie counts the runes of the words inserted, and the
+ * alphabet is made of those runes and the space, the letter of the root. It
+ * is derived again whenever it is needed after the counts changed: after a
+ * word with a new rune is inserted, or with OrderByFrequency after any word
+ * is inserted. So the dataBits of the encoded trie are the fewest needed, and
+ * the order is the one of all the inserted words. The alphabet is written by
+ * Save and CreateTrieData, so Load and TrieData.CreateFrozenTrie need not be
+ * given it.
+ */
+
+import "sort"
+
+/**
+  The order of the letters of a derived alphabet, which is the order of the
+  children in the encoded trie and of the words returned by FrozenTrie.Iter.
+*/
+type AlphabetOrder int
+
+const (
+	// the order of the code points of the letters
+	OrderByCodePoint AlphabetOrder = iota
+	// the most frequent letter first, letters as frequent in the order of
+	// their code points
+	OrderByFrequency
+)
+
+/**
+  Initialize the trie with an alphabet derived from the inserted words, see
+  GetAlphabet. Insert accepts any letter.
+*/
+func (t *Trie) InitWithDerivedAlphabet(order AlphabetOrder) {
+	t.InitWithAlphabet(nil)
+	t.alphabetOrder = order
+	t.runeCounts = map[rune]uint64{}
+}
+
+// countRunes counts the runes of the word, and forgets the derived alphabet
+// if it depends on the counts which changed.
+func (t *Trie) countRunes(word string) {
+	for _, runeValue := range word {
+		if t.runeCounts[runeValue] == 0 || t.alphabetOrder == OrderByFrequency {
+			t.alphabet = nil
+		}
+		t.runeCounts[runeValue]++
+	}
+}
+
+// deriveAlphabet returns the alphabet of the counted runes and the space.
+func deriveAlphabet(counts map[rune]uint64, order AlphabetOrder) *Alphabet {
+	letters := []rune{' '}
+	for runeValue := range counts {
+		if runeValue != ' ' {
+			letters = append(letters, runeValue)
+		}
+	}
+
+	sort.Slice(letters, func(i, j int) bool {
+		if order == OrderByFrequency && counts[letters[i]] != counts[letters[j]] {
+			return counts[letters[i]] > counts[letters[j]]
+		}
+		return letters[i] < letters[j]
+	})
+	return CreateAlphabet(string(letters))
+}
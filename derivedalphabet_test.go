@@ -0,0 +1,85 @@
+package bits
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDerivedAlphabet(t *testing.T) {
+	te := Trie{}
+	te.InitWithDerivedAlphabet(OrderByCodePoint)
+	for _, word := range []string{"dhammaṃ", "saṅgha", "buddhā"} {
+		if err := te.Insert(word); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if chars := te.GetAlphabet().GetCharacters(); chars != " abdghmsuāṃṅ" {
+		t.Errorf("GetCharacters %q", chars)
+	}
+	// 12 letters take 4 bits
+	if te.GetAlphabet().GetDataBits() != 5 {
+		t.Error("GetDataBits", te.GetAlphabet().GetDataBits())
+	}
+
+	// a new letter derives the alphabet again
+	te.Insert("vihāra ti")
+	if chars := te.GetAlphabet().GetCharacters(); chars != " abdghimrstuvāṃṅ" {
+		t.Errorf("GetCharacters %q", chars)
+	}
+
+	var buf bytes.Buffer
+	if err := te.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	ft, err := Load(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, word := range []string{"dhammaṃ", "saṅgha", "buddhā", "vihāra ti"} {
+		if !ft.Lookup(word) {
+			t.Error("Lookup", word)
+		}
+	}
+
+	td := CreateTrieData(&te)
+	ft2, err := td.CreateFrozenTrie(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ft2.Lookup("saṅgha") {
+		t.Error("CreateFrozenTrie")
+	}
+}
+
+func TestDerivedAlphabetByFrequency(t *testing.T) {
+	te := Trie{}
+	te.InitWithDerivedAlphabet(OrderByFrequency)
+	te.Insert("banana")
+	te.Insert("cab")
+	if chars := te.GetAlphabet().GetCharacters(); chars != "abnc " {
+		t.Errorf("GetCharacters %q", chars)
+	}
+
+	// the children are encoded in the order of the alphabet
+	ft := FrozenTrie{}
+	data := te.Encode()
+	rd := CreateRankDirectory(data, te.GetNodeCount()*2+1, L1, L2)
+	ft.InitWithAlphabet(data, rd.GetData(), te.GetNodeCount(), te.GetAlphabet())
+	var words []string
+	for it := ft.Iter(); ; {
+		word, ok := it.Next()
+		if !ok {
+			break
+		}
+		words = append(words, word)
+	}
+	if len(words) != 2 || words[0] != "banana" || words[1] != "cab" {
+		t.Error("Iter", words)
+	}
+
+	// the order follows the counts of letters which are not new
+	te.Insert("ccc")
+	if chars := te.GetAlphabet().GetCharacters(); chars != "acbn " {
+		t.Errorf("GetCharacters after more c %q", chars)
+	}
+}
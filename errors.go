@@ -0,0 +1,36 @@
+package bits
+
+import (
+	"errors"
+	"strconv"
+)
+
+// ErrNoSpace is returned by Trie and StreamBuilder if their alphabet has no
+// space, which is the letter of the root.
+var ErrNoSpace = errors.New("bits: alphabet has no space")
+
+/**
+  IllegalCharacterError is returned by Trie.Insert if the word contains a
+  letter which is not in the alphabet of the trie.
+*/
+type IllegalCharacterError struct {
+	Word string
+	Rune rune
+}
+
+func (e *IllegalCharacterError) Error() string {
+	return "bits: illegal character " + strconv.QuoteRune(e.Rune) +
+		" in word " + strconv.Quote(e.Word)
+}
+
+/**
+  DataError is returned by the validating constructors of FrozenTrie if the
+  encoded data is not a valid trie.
+*/
+type DataError struct {
+	Reason string
+}
+
+func (e *DataError) Error() string {
+	return "bits: invalid trie data: " + e.Reason
+}
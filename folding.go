@@ -0,0 +1,152 @@
+package bits
+
+/**
+ * Diacritic-insensitive suggestions. A folding map gives the base letter of
+ * the letters of the alphabet, for example "a" for "ā" and "m" for "ṃ". The
+ * letters of the prefix are then matched by every child whose base letter is
+ * the base letter of the typed one, so "dhamma" finds "dhammaṃ" and
+ * "dhammā" as well as "dhamma". The words keep their original spelling and
+ * are ranked by the number of letters of the prefix typed differently.
+ */
+
+import "sort"
+
+/**
+  A FoldingMap maps letters to their base letters. Letters which are not in
+  the map are their own base letter.
+*/
+type FoldingMap struct {
+	bases map[string]string
+}
+
+/**
+  Create an empty folding map.
+*/
+func CreateFoldingMap() *FoldingMap {
+	return &FoldingMap{bases: map[string]string{}}
+}
+
+/**
+  Set the base letter of the letter.
+*/
+func (m *FoldingMap) Set(letter, base string) {
+	m.bases[letter] = base
+}
+
+/**
+  Returns the base letter of the letter.
+*/
+func (m *FoldingMap) Fold(letter string) string {
+	if m != nil {
+		if base, ok := m.bases[letter]; ok {
+			return base
+		}
+	}
+	return letter
+}
+
+/**
+  Set the folding map used by SuggestFolded.
+*/
+func (f *FrozenTrie) SetFoldingMap(m *FoldingMap) {
+	f.folding = m
+}
+
+/**
+  Returns the folding map of the trie, or nil if it has none.
+*/
+func (f *FrozenTrie) GetFoldingMap() *FoldingMap {
+	return f.folding
+}
+
+// foldedNode is a node matching the prefix, with the number of letters of
+// the prefix which are spelled differently on its path.
+type foldedNode struct {
+	index uint
+	word  string
+	cost  int
+}
+
+/**
+  Returns at most limit words of the trie starting with a prefix which folds
+  to the same letters as prefix, see SetFoldingMap, in their original
+  spelling. The words whose prefix has the fewest letters different from
+  prefix come first, then the shorter words, then the words in the order of
+  the alphabet. The prefix is normalized first if the trie has normalizers.
+*/
+func (f *FrozenTrie) SuggestFolded(prefix string, limit int) []string {
+	var result []string
+	if limit <= 0 {
+		return result
+	}
+
+	var letters []string
+	for _, runeValue := range f.normalizeWord(prefix) {
+		letters = append(letters, string(runeValue))
+	}
+
+	// the nodes at the end of every folded spelling of the prefix
+	matches := []foldedNode{{index: 0}}
+	for _, letter := range letters {
+		base := f.folding.Fold(letter)
+		var next []foldedNode
+		for _, m := range matches {
+			children := f.getSortedChildren(m.index)
+			var i uint
+			for i = 0; i < children.childCount; i++ {
+				child := children.get(i)
+				childLetter := f.getLetter(child)
+				if f.folding.Fold(childLetter) != base {
+					continue
+				}
+				cost := m.cost
+				if childLetter != letter {
+					cost++
+				}
+				next = append(next, foldedNode{index: child, word: m.word + childLetter, cost: cost})
+			}
+		}
+		matches = next
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].cost < matches[j].cost
+	})
+
+	// the completions of the matches of the same cost, level by level
+	for start := 0; start < len(matches); {
+		end := start
+		for end < len(matches) && matches[end].cost == matches[start].cost {
+			end++
+		}
+
+		level := matches[start:end]
+		for len(level) > 0 {
+			var words []string
+			var next []foldedNode
+			for _, m := range level {
+				if f.isFinal(m.index) {
+					words = append(words, m.word)
+				}
+				children := f.getSortedChildren(m.index)
+				var i uint
+				for i = 0; i < children.childCount; i++ {
+					child := children.get(i)
+					next = append(next, foldedNode{index: child, word: m.word + f.getLetter(child), cost: m.cost})
+				}
+			}
+
+			sort.Slice(words, func(i, j int) bool {
+				return f.alphabet.Compare(words[i], words[j]) < 0
+			})
+			for _, word := range words {
+				result = append(result, word)
+				if len(result) == limit {
+					return result
+				}
+			}
+			level = next
+		}
+		start = end
+	}
+	return result
+}
@@ -0,0 +1,47 @@
+package bits
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSuggestFolded(t *testing.T) {
+	alphabet := CreateAlphabet("abcdeghijklmnoprstuvyāīūṁṃŋṇṅñṭḍḷ…'’° -")
+	te := Trie{}
+	te.InitWithAlphabet(alphabet)
+	for _, word := range []string{"dhamma", "dhammaṃ", "dhammā", "dhāmmā", "saṅgha", "saṅghaṃ", "sanati"} {
+		te.Insert(word)
+	}
+	data := te.Encode()
+	rd := CreateRankDirectory(data, te.GetNodeCount()*2+1, L1, L2)
+	ft := FrozenTrie{}
+	ft.InitWithAlphabet(data, rd.GetData(), te.GetNodeCount(), alphabet)
+
+	// without a folding map, the letters must be the same
+	if words := ft.SuggestFolded("sang", 10); len(words) != 0 {
+		t.Error("SuggestFolded without folding map", words)
+	}
+
+	m := CreateFoldingMap()
+	m.Set("ā", "a")
+	m.Set("ṃ", "m")
+	m.Set("ṅ", "n")
+	ft.SetFoldingMap(m)
+
+	// the exact prefix first, then the shorter words
+	if words := ft.SuggestFolded("dhamma", 10); !reflect.DeepEqual(words, []string{"dhamma", "dhammaṃ", "dhammā", "dhāmmā"}) {
+		t.Error("SuggestFolded dhamma", words)
+	}
+	if words := ft.SuggestFolded("dhāmm", 10); !reflect.DeepEqual(words, []string{"dhāmmā", "dhamma", "dhammā", "dhammaṃ"}) {
+		t.Error("SuggestFolded dhāmm", words)
+	}
+	if words := ft.SuggestFolded("san", 10); !reflect.DeepEqual(words, []string{"sanati", "saṅgha", "saṅghaṃ"}) {
+		t.Error("SuggestFolded san", words)
+	}
+	if words := ft.SuggestFolded("san", 2); !reflect.DeepEqual(words, []string{"sanati", "saṅgha"}) {
+		t.Error("SuggestFolded limit", words)
+	}
+	if words := ft.SuggestFolded("x", 10); len(words) != 0 {
+		t.Error("SuggestFolded x", words)
+	}
+}
@@ -40,20 +40,85 @@ func (f *FrozenTrieNode) GetChild(index uint) FrozenTrieNode {
   and L2 constants are used to determine the L1Size and L2size.
 
   @param nodeCount The number of nodes in the trie.
+
+  @param alphabet The alphabet the trie was encoded with. Init uses the
+  default alphabet.
 */
 type FrozenTrie struct {
-	data        BitString
+	data        BitVector
 	directory   RankDirectory
 	letterStart uint
+	nodeCount   uint
+	alphabet    *Alphabet
+
+	// whether the children of every node are in the order of the alphabet
+	sortedChildren bool
+
+	// rank directory of the "final" indicators, built on first use, see
+	// wordid.go
+	finals *lazyFinals
+	// values of the words, see values.go
+	values packedArray
+	// weights of the words and the maximum weight in the subtrie of each
+	// node, see weights.go
+	weights    packedArray
+	maxWeights packedArray
+	// failure and output links of the nodes, see matcher.go
+	failLinks packedArray
+	outLinks  packedArray
+	maxDepth  uint
+	// trie of the reversed words, see suffix.go
+	suffixes *FrozenTrie
+	// see normalizer.go
+	normalizers []string
+	normalize   Normalizer
+	// see folding.go
+	folding *FoldingMap
 }
 
 func (f *FrozenTrie) Init(data, directoryData string, nodeCount uint) {
-	f.data.Init(data)
-	f.directory.Init(directoryData, data, nodeCount*2+1, L1, L2)
+	f.InitWithAlphabet(data, directoryData, nodeCount, defaultAlphabet)
+}
+
+func (f *FrozenTrie) InitWithAlphabet(data, directoryData string, nodeCount uint, alphabet *Alphabet) {
+	bits := &BitString{}
+	bits.Init(data)
+	directory := &BitString{}
+	directory.Init(directoryData)
+	f.init(bits, directory, nodeCount, L1, L2, alphabet)
+}
+
+/**
+  Same as InitWithAlphabet, but for the data and directory stored in 64-bit
+  words, as returned by Trie.EncodeBinary and RankDirectory.GetWords.
+*/
+func (f *FrozenTrie) InitBinary(data, directoryData []uint64, nodeCount uint, alphabet *Alphabet) {
+	bits := &BinaryBitString{}
+	bits.Init(data)
+	directory := &BinaryBitString{}
+	directory.Init(directoryData)
+	f.init(bits, directory, nodeCount, L1, L2, alphabet)
+}
+
+func (f *FrozenTrie) init(data, directory BitVector, nodeCount, l1Size, l2Size uint, alphabet *Alphabet) {
+	f.alphabet = alphabet
+	f.nodeCount = nodeCount
+	f.data = data
+	f.directory.init(directory, data, nodeCount*2+1, l1Size, l2Size)
 
 	// The position of the first bit of the data in 0th node. In non-root
 	// nodes, this would contain 6-bit letters.
 	f.letterStart = nodeCount*2 + 1
+
+	f.sortedChildren = false
+	f.finals = &lazyFinals{}
+}
+
+/**
+  Returns the number of nodes in the trie.
+*/
+func (f *FrozenTrie) GetNodeCount() uint {
+	return f.nodeCount
 }
 
 /**
@@ -62,11 +127,8 @@ func (f *FrozenTrie) Init(data, directoryData string, nodeCount uint) {
 */
 func (f *FrozenTrie) GetNodeByIndex(index uint) FrozenTrieNode {
 	// retrieve the (dataBits)-bit letter.
-	final := (f.data.Get(f.letterStart+index*dataBits, 1) == 1)
-	letter, ok := mapUintToChar[f.data.Get(f.letterStart+index*dataBits+1, (dataBits-1))]
-	if !ok {
-		panic("illegal: bits -> char failed")
-	}
+	final := f.isFinal(index)
+	letter := f.getLetter(index)
 	firstChild := f.directory.Select(0, index+1) - index
 
 	// Since the nodes are in level order, this nodes children must go up
@@ -83,6 +145,13 @@ func (f *FrozenTrie) GetNodeByIndex(index uint) FrozenTrieNode {
 	}
 }
 
+/**
+  Returns the alphabet of the trie.
+*/
+func (f *FrozenTrie) GetAlphabet() *Alphabet {
+	return f.alphabet
+}
+
 /**
   Retrieve the root node. You can use this node to obtain all of the other
   nodes in the trie.
@@ -93,27 +162,72 @@ func (f *FrozenTrie) GetRoot() FrozenTrieNode {
 
 /**
   Look-up a word in the trie. Returns true if and only if the word exists
-  in the trie.
+  in the trie. The word is normalized first if the trie has normalizers.
 */
 func (f *FrozenTrie) Lookup(word string) bool {
+	node, ok := f.findNode(f.normalizeWord(word))
+	return ok && node.final
+}
+
+// findNode returns the node at the end of the path spelled by word.
+func (f *FrozenTrie) findNode(word string) (FrozenTrieNode, bool) {
 	node := f.GetRoot()
 	for i, w := 0, 0; i < len(word); i += w {
 		runeValue, width := utf8.DecodeRuneInString(word[i:])
 		w = width
-		var child FrozenTrieNode
-		var j uint = 0
-		for ; j < node.GetChildCount(); j++ {
-			child = node.GetChild(j)
-			if child.letter == string(runeValue) {
-				break
+		child, ok := f.findChild(node, string(runeValue))
+		if !ok {
+			return node, false
+		}
+		node = child
+	}
+
+	return node, true
+}
+
+/**
+  Returns the child of the node with the given letter, and true if there is
+  one. Only the letter codes of the other children are read from the data,
+  by binary search if the children are sorted.
+*/
+func (f *FrozenTrie) findChild(node FrozenTrieNode, letter string) (FrozenTrieNode, bool) {
+	code, ok := f.alphabet.encode(letter)
+	if !ok {
+		return node, false
+	}
+
+	low, high := node.firstChild, node.firstChild+node.childCount
+	if !f.sortedChildren {
+		for ; low < high; low++ {
+			if f.getCode(low) == code {
+				return f.GetNodeByIndex(low), true
 			}
 		}
+		return node, false
+	}
 
-		if j == node.GetChildCount() {
-			return false
+	for low < high {
+		mid := low + (high-low)/2
+		c := f.getCode(mid)
+		if c == code {
+			return f.GetNodeByIndex(mid), true
+		}
+		if c < code {
+			low = mid + 1
+		} else {
+			high = mid
 		}
-		node = child
 	}
+	return node, false
+}
 
-	return node.final
+/**
+  Tells the trie whether the children of every node are in the order of the
+  alphabet, so that lookups can binary search them. Trie.Encode writes the
+  children in order, but old versions wrote them in the order they were
+  inserted, so Init assumes they are not. Load and TrieData.CreateFrozenTrie
+  set it from the recorded flag.
+*/
+func (f *FrozenTrie) SetSortedChildren(sorted bool) {
+	f.sortedChildren = sorted
 }
@@ -46,3 +46,48 @@ func TestLookup(t *testing.T) {
 		t.Error("alphapha")
 	}
 }
+
+func TestLookupUnsortedChildren(t *testing.T) {
+	// encoded by an old version, which wrote the children in the order
+	// they were inserted.
+	ft := FrozenTrie{}
+	ft.Init("v2qqqqqqqlFEjQBxY5KB5aIAgih54BhZZBY5t5ZfMgA11x8g8A", "BMIg", 37)
+	if ft.sortedChildren != false {
+		t.Error("ft.sortedChildren != false")
+	}
+	for _, word := range []string{"apple", "orange", "alphapha", "lamp", "hello", "jello", "quiz"} {
+		if ft.Lookup(word) != true {
+			t.Error(word)
+		}
+	}
+	if ft.Lookup("alpha") != false {
+		t.Error("alpha")
+	}
+
+	te := Trie{}
+	te.Init()
+	insertNotInAlphabeticalOrder(&te)
+	teData := te.Encode()
+	if teData != "v2qqqqqqqpIUjQA5JZyBZ4ggCKh55ZZgBA5ZZd5vIEl1wx8g8A" {
+		t.Error("children not sorted, got ", teData)
+	}
+	td := CreateTrieData(&te)
+	fs, err := td.CreateFrozenTrie(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fs.sortedChildren != true {
+		t.Error("fs.sortedChildren != true")
+	}
+
+	// old data which claims to be sorted is rejected
+	old := TrieData{
+		EncodedData:       "v2qqqqqqqlFEjQBxY5KB5aIAgih54BhZZBY5t5ZfMgA11x8g8A",
+		NodeCount:         37,
+		RankDirectoryData: "BMIg",
+		SortedChildren:    true,
+	}
+	if _, err := old.CreateFrozenTrie(DefaultAlphabet()); err == nil {
+		t.Error("unsorted children accepted as sorted")
+	}
+}
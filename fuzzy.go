@@ -0,0 +1,155 @@
+package bits
+
+/**
+ * Fuzzy lookup by edit distance. The trie is traversed depth-first, keeping
+ * one row of the Levenshtein dynamic programming table per node: row[j] is
+ * the distance between the letters on the path to the node and the first j
+ * letters of the word. A branch is pruned as soon as every entry of its row
+ * is larger than the maximum distance.
+ */
+
+import "sort"
+
+/**
+  A word of the trie found by FuzzySearch, with its distance to the searched
+  word.
+*/
+type FuzzyMatch struct {
+	Word     string
+	Distance int
+}
+
+/**
+  The costs of the edit operations used by FuzzySearchWithCosts. The cost of
+  substituting a letter can be set per pair of letters, so that for example
+  "a" and "ā" are closer than two arbitrary letters.
+*/
+type CostTable struct {
+	insert        int
+	delete        int
+	substitute    int
+	substitutions map[string]map[string]int
+}
+
+/**
+  Create a cost table with the given cost of inserting, deleting and
+  substituting a letter.
+*/
+func CreateCostTable(insert, delete, substitute int) *CostTable {
+	return &CostTable{
+		insert:        insert,
+		delete:        delete,
+		substitute:    substitute,
+		substitutions: map[string]map[string]int{},
+	}
+}
+
+/**
+  Set the cost of substituting a by b, and b by a.
+*/
+func (c *CostTable) SetSubstitution(a, b string, cost int) {
+	for _, pair := range [2][2]string{{a, b}, {b, a}} {
+		m, ok := c.substitutions[pair[0]]
+		if !ok {
+			m = map[string]int{}
+			c.substitutions[pair[0]] = m
+		}
+		m[pair[1]] = cost
+	}
+}
+
+func (c *CostTable) substitution(a, b string) int {
+	if a == b {
+		return 0
+	}
+	if cost, ok := c.substitutions[a][b]; ok {
+		return cost
+	}
+	return c.substitute
+}
+
+var unitCosts = CreateCostTable(1, 1, 1)
+
+/**
+  Returns at most limit words of the trie whose Levenshtein distance to word
+  is at most maxDist, the closest first. Words at the same distance are in
+  the order of the alphabet of the trie. The word is normalized first if the trie has normalizers.
+*/
+func (f *FrozenTrie) FuzzySearch(word string, maxDist int, limit int) []FuzzyMatch {
+	return f.FuzzySearchWithCosts(word, maxDist, limit, unitCosts)
+}
+
+/**
+  Same as FuzzySearch, but with the costs of the edit operations taken from
+  the cost table.
+*/
+func (f *FrozenTrie) FuzzySearchWithCosts(word string, maxDist int, limit int, costs *CostTable) []FuzzyMatch {
+	var result []FuzzyMatch
+	if limit <= 0 {
+		return result
+	}
+
+	var letters []string
+	for _, runeValue := range f.normalizeWord(word) {
+		letters = append(letters, string(runeValue))
+	}
+
+	row := make([]int, len(letters)+1)
+	for j := 1; j <= len(letters); j++ {
+		row[j] = row[j-1] + costs.insert
+	}
+
+	fs := fuzzySearch{trie: f, letters: letters, maxDist: maxDist, costs: costs}
+	fs.visit(f.GetRoot(), "", row)
+
+	sort.Slice(fs.matches, func(i, j int) bool {
+		if fs.matches[i].Distance != fs.matches[j].Distance {
+			return fs.matches[i].Distance < fs.matches[j].Distance
+		}
+		return f.alphabet.Compare(fs.matches[i].Word, fs.matches[j].Word) < 0
+	})
+	if len(fs.matches) > limit {
+		fs.matches = fs.matches[:limit]
+	}
+	return append(result, fs.matches...)
+}
+
+type fuzzySearch struct {
+	trie    *FrozenTrie
+	letters []string
+	maxDist int
+	costs   *CostTable
+	matches []FuzzyMatch
+}
+
+func (fs *fuzzySearch) visit(node FrozenTrieNode, prefix string, row []int) {
+	n := len(fs.letters)
+	if node.final && row[n] <= fs.maxDist {
+		fs.matches = append(fs.matches, FuzzyMatch{Word: prefix, Distance: row[n]})
+	}
+
+	var i uint = 0
+	for ; i < node.GetChildCount(); i++ {
+		child := node.GetChild(i)
+
+		next := make([]int, n+1)
+		next[0] = row[0] + fs.costs.delete
+		min := next[0]
+		for j := 1; j <= n; j++ {
+			next[j] = row[j-1] + fs.costs.substitution(child.letter, fs.letters[j-1])
+			if d := row[j] + fs.costs.delete; d < next[j] {
+				next[j] = d
+			}
+			if d := next[j-1] + fs.costs.insert; d < next[j] {
+				next[j] = d
+			}
+			if next[j] < min {
+				min = next[j]
+			}
+		}
+
+		if min <= fs.maxDist {
+			fs.visit(child, prefix+child.letter, next)
+		}
+	}
+}
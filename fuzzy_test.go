@@ -0,0 +1,72 @@
+package bits
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestFuzzySearch(t *testing.T) {
+	te := Trie{}
+	te.Init()
+	insertNotInAlphabeticalOrder(&te)
+	teData := te.Encode()
+	rd := CreateRankDirectory(teData, te.GetNodeCount()*2+1, L1, L2)
+	ft := FrozenTrie{}
+	ft.Init(teData, rd.GetData(), te.GetNodeCount())
+
+	expected := []FuzzyMatch{{"hello", 1}, {"jello", 2}}
+	if !reflect.DeepEqual(ft.FuzzySearch("helo", 2, 10), expected) {
+		t.Error(`FuzzySearch("helo", 2, 10)`, ft.FuzzySearch("helo", 2, 10))
+	}
+	if !reflect.DeepEqual(ft.FuzzySearch("helo", 2, 1), expected[:1]) {
+		t.Error(`FuzzySearch("helo", 2, 1)`, ft.FuzzySearch("helo", 2, 1))
+	}
+	if len(ft.FuzzySearch("xyz", 1, 10)) != 0 {
+		t.Error(`FuzzySearch("xyz", 1, 10)`)
+	}
+	if !reflect.DeepEqual(ft.FuzzySearch("lamp", 0, 10), []FuzzyMatch{{"lamp", 0}}) {
+		t.Error(`FuzzySearch("lamp", 0, 10)`)
+	}
+}
+
+func TestFuzzySearchWithCosts(t *testing.T) {
+	pali := CreateAlphabet("abcdeghijklmnoprstuvyāīūṁṃŋṇṅñṭḍḷ…'’° -")
+	te := Trie{}
+	te.InitWithAlphabet(pali)
+	te.Insert("dhammaṃ")
+	te.Insert("dhammo")
+	te.Insert("kāya")
+	te.Insert("kaya")
+	buf := &bytes.Buffer{}
+	if err := te.Save(buf); err != nil {
+		t.Fatal(err)
+	}
+	ft, err := Load(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	costs := CreateCostTable(2, 2, 2)
+	costs.SetSubstitution("a", "ā", 1)
+	costs.SetSubstitution("m", "ṃ", 1)
+
+	expected := []FuzzyMatch{{"dhammaṃ", 1}, {"dhammo", 4}}
+	if !reflect.DeepEqual(ft.FuzzySearchWithCosts("dhammam", 4, 10, costs), expected) {
+		t.Error(`FuzzySearchWithCosts("dhammam", 4, 10)`, ft.FuzzySearchWithCosts("dhammam", 4, 10, costs))
+	}
+	// in the order of the alphabet, "ā" is before "ñ", not after
+	te.Insert("kñya")
+	buf.Reset()
+	te.Save(buf)
+	ft, _ = Load(buf)
+	expected = []FuzzyMatch{{"kaya", 1}, {"kāya", 1}, {"kñya", 1}}
+	if !reflect.DeepEqual(ft.FuzzySearch("kxya", 1, 10), expected) {
+		t.Error(`FuzzySearch("kxya", 1, 10)`, ft.FuzzySearch("kxya", 1, 10))
+	}
+
+	expected = []FuzzyMatch{{"kaya", 0}, {"kāya", 1}}
+	if !reflect.DeepEqual(ft.FuzzySearchWithCosts("kaya", 1, 10, costs), expected) {
+		t.Error(`FuzzySearchWithCosts("kaya", 1, 10)`, ft.FuzzySearchWithCosts("kaya", 1, 10, costs))
+	}
+}
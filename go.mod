@@ -1,3 +1,5 @@
 module github.com/siongui/go-succinct-data-structure-trie
 
 go 1.17
+
+require golang.org/x/text v0.3.8
@@ -0,0 +1,332 @@
+// Package httptrie serves the words of a succinct trie over HTTP, as JSON:
+//
+//	GET /lookup?word=dhamma                    {"word":"dhamma","found":true}
+//	GET /suggest?prefix=dh&limit=10            {"prefix":"dh","words":[...]}
+//	GET /fuzzy?word=damma&distance=1&limit=10  {"word":"damma","matches":[...]}
+//	GET /metrics                               the request metrics
+//
+// The trie is read from a file in the JSON format of bits.TrieData, and read
+// again by Reload or Watch when the file changes.
+package httptrie
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	bits "github.com/siongui/go-succinct-data-structure-trie"
+)
+
+// limits of the query parameters
+const (
+	DefaultLimit     = 10
+	MaxLimit         = 1000
+	DefaultDistance  = 1
+	MaxFuzzyDistance = 3
+)
+
+/**
+  Handler is the http.Handler of the endpoints. It is safe for concurrent
+  use, the trie being replaced while requests are served.
+*/
+type Handler struct {
+	path     string
+	alphabet *bits.Alphabet
+	mux      *http.ServeMux
+
+	mu      sync.RWMutex
+	trie    *bits.FrozenTrie
+	modTime time.Time
+	size    int64
+
+	metricsMu sync.Mutex
+	metrics   Metrics
+}
+
+/**
+  The counters of an endpoint. Errors are the requests answered with a
+  status code of 400 or more.
+*/
+type EndpointMetrics struct {
+	Requests  uint64        `json:"requests"`
+	Errors    uint64        `json:"errors"`
+	TotalTime time.Duration `json:"totalTimeNs"`
+}
+
+/**
+  The request metrics of a Handler, and the state of its trie file.
+*/
+type Metrics struct {
+	Endpoints    map[string]EndpointMetrics `json:"endpoints"`
+	Reloads      uint64                     `json:"reloads"`
+	ReloadErrors uint64                     `json:"reloadErrors"`
+	LoadedAt     time.Time                  `json:"loadedAt"`
+	Words        uint                       `json:"words"`
+}
+
+/**
+  Create a Handler serving the trie read from the file at path. The alphabet
+  is used if the file has none, see bits.TrieData.
+*/
+func CreateHandler(path string, alphabet *bits.Alphabet) (*Handler, error) {
+	h := &Handler{path: path, alphabet: alphabet}
+	h.init()
+	if _, err := h.reload(); err != nil {
+		return nil, err
+	}
+	h.metrics.LoadedAt = time.Now()
+	h.metrics.Words = h.trie.GetWordCount()
+	return h, nil
+}
+
+/**
+  Create a Handler serving the given trie, which is never reloaded.
+*/
+func CreateHandlerForTrie(trie *bits.FrozenTrie) *Handler {
+	h := &Handler{trie: trie}
+	h.init()
+	h.metrics.LoadedAt = time.Now()
+	h.metrics.Words = trie.GetWordCount()
+	return h
+}
+
+func (h *Handler) init() {
+	h.metrics.Endpoints = map[string]EndpointMetrics{}
+	h.mux = http.NewServeMux()
+	h.handle("/lookup", h.lookup)
+	h.handle("/suggest", h.suggest)
+	h.handle("/fuzzy", h.fuzzy)
+	h.handle("/metrics", h.serveMetrics)
+}
+
+/**
+  Read the trie file again if its modification time or size changed since it
+  was read. Returns true if the trie was replaced. On errors, the trie read
+  before is kept.
+*/
+func (h *Handler) Reload() (bool, error) {
+	if h.path == "" {
+		return false, nil
+	}
+	reloaded, err := h.reload()
+
+	h.metricsMu.Lock()
+	defer h.metricsMu.Unlock()
+	if err != nil {
+		h.metrics.ReloadErrors++
+	} else if reloaded {
+		h.metrics.Reloads++
+		h.metrics.LoadedAt = time.Now()
+		h.metrics.Words = h.getTrie().GetWordCount()
+	}
+	return reloaded, err
+}
+
+func (h *Handler) reload() (bool, error) {
+	info, err := os.Stat(h.path)
+	if err != nil {
+		return false, err
+	}
+	h.mu.RLock()
+	unchanged := h.trie != nil && info.ModTime().Equal(h.modTime) && info.Size() == h.size
+	h.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	b, err := ioutil.ReadFile(h.path)
+	if err != nil {
+		return false, err
+	}
+	var td bits.TrieData
+	if err := json.Unmarshal(b, &td); err != nil {
+		return false, err
+	}
+	trie, err := td.CreateFrozenTrie(h.alphabet)
+	if err != nil {
+		return false, err
+	}
+
+	h.mu.Lock()
+	h.trie = trie
+	h.modTime = info.ModTime()
+	h.size = info.Size()
+	h.mu.Unlock()
+	return true, nil
+}
+
+/**
+  Call Reload every interval until stop is closed. Errors are passed to
+  onError if it is not nil.
+*/
+func (h *Handler) Watch(interval time.Duration, stop <-chan struct{}, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if _, err := h.Reload(); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+func (h *Handler) getTrie() *bits.FrozenTrie {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.trie
+}
+
+/**
+  Returns a copy of the metrics.
+*/
+func (h *Handler) GetMetrics() Metrics {
+	h.metricsMu.Lock()
+	defer h.metricsMu.Unlock()
+	m := h.metrics
+	m.Endpoints = make(map[string]EndpointMetrics, len(h.metrics.Endpoints))
+	for path, em := range h.metrics.Endpoints {
+		m.Endpoints[path] = em
+	}
+	return m
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// badRequestError is the error of a bad query parameter.
+type badRequestError struct {
+	message string
+}
+
+func (e *badRequestError) Error() string {
+	return e.message
+}
+
+// handle registers the endpoint, which returns the value to write as JSON.
+func (h *Handler) handle(path string, endpoint func(*bits.FrozenTrie, *http.Request) (interface{}, error)) {
+	h.mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		status := http.StatusOK
+		var body interface{}
+
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			status = http.StatusMethodNotAllowed
+			w.Header().Set("Allow", "GET, HEAD")
+			body = errorBody{"method not allowed"}
+		} else if value, err := endpoint(h.getTrie(), r); err != nil {
+			status = http.StatusBadRequest
+			body = errorBody{err.Error()}
+		} else {
+			body = value
+		}
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(status)
+		if r.Method != http.MethodHead {
+			json.NewEncoder(w).Encode(body)
+		}
+		h.count(path, status, time.Since(start))
+	})
+}
+
+func (h *Handler) count(path string, status int, d time.Duration) {
+	h.metricsMu.Lock()
+	defer h.metricsMu.Unlock()
+	em := h.metrics.Endpoints[path]
+	em.Requests++
+	if status >= 400 {
+		em.Errors++
+	}
+	em.TotalTime += d
+	h.metrics.Endpoints[path] = em
+}
+
+type errorBody struct {
+	Error string `json:"error"`
+}
+
+type lookupBody struct {
+	Word  string `json:"word"`
+	Found bool   `json:"found"`
+}
+
+type suggestBody struct {
+	Prefix string   `json:"prefix"`
+	Words  []string `json:"words"`
+}
+
+type fuzzyMatch struct {
+	Word     string `json:"word"`
+	Distance int    `json:"distance"`
+}
+
+type fuzzyBody struct {
+	Word    string       `json:"word"`
+	Matches []fuzzyMatch `json:"matches"`
+}
+
+// intParam returns the integer query parameter, or def if there is none.
+func intParam(r *http.Request, name string, def, max int) (int, error) {
+	s := r.URL.Query().Get(name)
+	if s == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 || n > max {
+		return 0, &badRequestError{name + " must be an integer from 0 to " + strconv.Itoa(max)}
+	}
+	return n, nil
+}
+
+func (h *Handler) lookup(trie *bits.FrozenTrie, r *http.Request) (interface{}, error) {
+	word := r.URL.Query().Get("word")
+	return lookupBody{Word: word, Found: trie.Lookup(word)}, nil
+}
+
+func (h *Handler) suggest(trie *bits.FrozenTrie, r *http.Request) (interface{}, error) {
+	prefix := r.URL.Query().Get("prefix")
+	limit, err := intParam(r, "limit", DefaultLimit, MaxLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	words := []string{}
+	if limit > 0 {
+		words = append(words, trie.GetSuggestedWords(prefix, limit)...)
+	}
+	if len(words) > limit {
+		words = words[:limit]
+	}
+	return suggestBody{Prefix: prefix, Words: words}, nil
+}
+
+func (h *Handler) fuzzy(trie *bits.FrozenTrie, r *http.Request) (interface{}, error) {
+	word := r.URL.Query().Get("word")
+	distance, err := intParam(r, "distance", DefaultDistance, MaxFuzzyDistance)
+	if err != nil {
+		return nil, err
+	}
+	limit, err := intParam(r, "limit", DefaultLimit, MaxLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := []fuzzyMatch{}
+	for _, m := range trie.FuzzySearch(word, distance, limit) {
+		matches = append(matches, fuzzyMatch{Word: m.Word, Distance: m.Distance})
+	}
+	return fuzzyBody{Word: word, Matches: matches}, nil
+}
+
+func (h *Handler) serveMetrics(trie *bits.FrozenTrie, r *http.Request) (interface{}, error) {
+	return h.GetMetrics(), nil
+}
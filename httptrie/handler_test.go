@@ -0,0 +1,198 @@
+package httptrie
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	bits "github.com/siongui/go-succinct-data-structure-trie"
+)
+
+func writeTrie(t *testing.T, path string, words ...string) {
+	te := bits.Trie{}
+	te.Init()
+	for _, word := range words {
+		te.Insert(word)
+	}
+	b, err := json.Marshal(bits.CreateTrieData(&te))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path, b, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func get(t *testing.T, h http.Handler, url string, body interface{}) int {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", url, nil))
+	if w.Header().Get("Content-Type") != "application/json; charset=utf-8" {
+		t.Error("Content-Type", url, w.Header().Get("Content-Type"))
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), body); err != nil {
+		t.Error(url, err)
+	}
+	return w.Code
+}
+
+func TestHandler(t *testing.T) {
+	dir, err := ioutil.TempDir("", "httptrie")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "trie.json")
+	writeTrie(t, path, "apple", "apply", "banana")
+
+	h, err := CreateHandler(path, bits.DefaultAlphabet())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var lookup lookupBody
+	if code := get(t, h, "/lookup?word=apple", &lookup); code != 200 || !lookup.Found || lookup.Word != "apple" {
+		t.Error("/lookup", code, lookup)
+	}
+
+	var suggest suggestBody
+	if code := get(t, h, "/suggest?prefix=app&limit=1", &suggest); code != 200 || !reflect.DeepEqual(suggest.Words, []string{"apple"}) {
+		t.Error("/suggest", code, suggest)
+	}
+	if code := get(t, h, "/suggest?prefix=x", &suggest); code != 200 || suggest.Words == nil || len(suggest.Words) != 0 {
+		t.Error("/suggest no words", code, suggest)
+	}
+
+	var fuzzy fuzzyBody
+	if code := get(t, h, "/fuzzy?word=appla", &fuzzy); code != 200 ||
+		!reflect.DeepEqual(fuzzy.Matches, []fuzzyMatch{{"apple", 1}, {"apply", 1}}) {
+		t.Error("/fuzzy", code, fuzzy)
+	}
+
+	var e errorBody
+	if code := get(t, h, "/suggest?limit=-1", &e); code != 400 || e.Error == "" {
+		t.Error("/suggest bad limit", code, e)
+	}
+	if code := get(t, h, "/fuzzy?distance=9", &e); code != 400 {
+		t.Error("/fuzzy bad distance", code, e)
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "/lookup?word=apple", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Error("POST", w.Code)
+	}
+
+	// hot reload
+	if reloaded, err := h.Reload(); reloaded || err != nil {
+		t.Error("Reload unchanged", reloaded, err)
+	}
+	writeTrie(t, path, "cherry")
+	later := time.Now().Add(time.Hour)
+	os.Chtimes(path, later, later)
+	if reloaded, err := h.Reload(); !reloaded || err != nil {
+		t.Error("Reload", reloaded, err)
+	}
+	if get(t, h, "/lookup?word=cherry", &lookup); !lookup.Found {
+		t.Error("/lookup after reload")
+	}
+
+	// a bad file keeps the trie
+	ioutil.WriteFile(path, []byte("{"), 0644)
+	if _, err := h.Reload(); err == nil {
+		t.Error("Reload bad file")
+	}
+	if get(t, h, "/lookup?word=cherry", &lookup); !lookup.Found {
+		t.Error("/lookup after bad reload")
+	}
+
+	var m Metrics
+	if code := get(t, h, "/metrics", &m); code != 200 {
+		t.Error("/metrics", code)
+	}
+	if em := m.Endpoints["/lookup"]; em.Requests != 4 || em.Errors != 1 {
+		t.Error("/lookup metrics", em)
+	}
+	if em := m.Endpoints["/suggest"]; em.Requests != 3 || em.Errors != 1 {
+		t.Error("/suggest metrics", em)
+	}
+	if m.Reloads != 1 || m.ReloadErrors != 1 || m.Words != 1 {
+		t.Error("reload metrics", m)
+	}
+}
+
+func TestCreateHandlerForTrie(t *testing.T) {
+	te := bits.Trie{}
+	te.Init()
+	te.Insert("hello")
+	td := bits.CreateTrieData(&te)
+	ft, err := td.CreateFrozenTrie(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := CreateHandlerForTrie(ft)
+	var lookup lookupBody
+	if get(t, h, "/lookup?word=hello", &lookup); !lookup.Found {
+		t.Error("/lookup")
+	}
+	if reloaded, err := h.Reload(); reloaded || err != nil {
+		t.Error("Reload", reloaded, err)
+	}
+
+	if _, err := CreateHandler(filepath.Join(os.TempDir(), "no-such-trie.json"), nil); err == nil {
+		t.Error("CreateHandler missing file")
+	}
+}
+
+func TestWatchCorruptFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "httptrie")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "trie.json")
+	writeTrie(t, path, "apple")
+
+	h, err := CreateHandler(path, bits.DefaultAlphabet())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a node count for which the length of the data overflows to the
+	// length of the data in the file
+	b, _ := ioutil.ReadFile(path)
+	var td bits.TrieData
+	if err := json.Unmarshal(b, &td); err != nil {
+		t.Fatal(err)
+	}
+	td.NodeCount += ^uint(0)/8 + 1
+	b, _ = json.Marshal(td)
+	ioutil.WriteFile(path, b, 0644)
+	later := time.Now().Add(time.Hour)
+	os.Chtimes(path, later, later)
+
+	errs := make(chan error, 1)
+	stop := make(chan struct{})
+	go h.Watch(time.Millisecond, stop, func(err error) {
+		select {
+		case errs <- err:
+		default:
+		}
+	})
+	err = <-errs
+	close(stop)
+	if _, ok := err.(*bits.DataError); !ok {
+		t.Error("expected *bits.DataError, got ", err)
+	}
+
+	var lookup lookupBody
+	if get(t, h, "/lookup?word=apple", &lookup); !lookup.Found {
+		t.Error("/lookup after corrupt reload")
+	}
+}
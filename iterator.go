@@ -0,0 +1,114 @@
+package bits
+
+/**
+ * Iteration over all the words of a FrozenTrie in the sort order of the
+ * alphabet, that is, the order of the letters in the string the alphabet was
+ * created with. The trie is traversed depth-first with an explicit stack, so
+ * the memory used is proportional to the length of the longest word.
+ */
+
+import (
+	"sort"
+	"strings"
+)
+
+/**
+  An Iterator returns the words of a FrozenTrie one at a time. Use
+  FrozenTrie.Iter to create one.
+*/
+type Iterator struct {
+	trie    *FrozenTrie
+	stack   []iteratorFrame
+	letters []string
+	pending bool // the empty word is in the trie and not returned yet
+}
+
+// iteratorFrame holds the children of a node on the current path, sorted by
+// letter, and the next one to visit.
+type iteratorFrame struct {
+	children childList
+	next     uint
+}
+
+/**
+  Returns an Iterator over the words of the trie.
+*/
+func (f *FrozenTrie) Iter() *Iterator {
+	it := &Iterator{trie: f}
+	it.push(0)
+	it.pending = f.isFinal(0)
+	return it
+}
+
+/**
+  Returns the next word and true, or false if all the words have been
+  returned.
+*/
+func (it *Iterator) Next() (string, bool) {
+	if it.pending {
+		it.pending = false
+		return "", true
+	}
+
+	for len(it.stack) > 0 {
+		top := &it.stack[len(it.stack)-1]
+		if top.next == top.children.childCount {
+			it.stack = it.stack[:len(it.stack)-1]
+			if len(it.letters) > 0 {
+				it.letters = it.letters[:len(it.letters)-1]
+			}
+			continue
+		}
+
+		child := top.children.get(top.next)
+		top.next++
+		it.letters = append(it.letters, it.trie.getLetter(child))
+		it.push(child)
+		if it.trie.isFinal(child) {
+			return strings.Join(it.letters, ""), true
+		}
+	}
+
+	return "", false
+}
+
+func (it *Iterator) push(index uint) {
+	it.stack = append(it.stack, iteratorFrame{children: it.trie.getSortedChildren(index)})
+}
+
+// childList is the children of a node in the order of the alphabet. In
+// sorted tries they are the childCount nodes from firstChild on, only the
+// children of old, unsorted data are sorted into indexes.
+type childList struct {
+	firstChild uint
+	childCount uint
+	indexes    []uint
+}
+
+// get returns the index of the ith child.
+func (c childList) get(i uint) uint {
+	if c.indexes != nil {
+		return c.indexes[i]
+	}
+	return c.firstChild + i
+}
+
+// getSortedChildren returns the children of the node in the order of the
+// alphabet.
+func (f *FrozenTrie) getSortedChildren(index uint) childList {
+	firstChild := f.directory.Select(0, index+1) - index
+	children := childList{
+		firstChild: firstChild,
+		childCount: f.directory.Select(0, index+2) - index - 1 - firstChild,
+	}
+	if !f.sortedChildren && children.childCount > 1 {
+		children.indexes = make([]uint, children.childCount)
+		for i := range children.indexes {
+			children.indexes[i] = firstChild + uint(i)
+		}
+		sort.Slice(children.indexes, func(i, j int) bool {
+			return f.getCode(children.indexes[i]) < f.getCode(children.indexes[j])
+		})
+	}
+	return children
+}
@@ -0,0 +1,21 @@
+//go:build go1.23
+// +build go1.23
+
+package bits
+
+import "iter"
+
+/**
+  Returns the words of the trie in the sort order of the alphabet, for use
+  in a range loop.
+*/
+func (f *FrozenTrie) All() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		it := f.Iter()
+		for word, ok := it.Next(); ok; word, ok = it.Next() {
+			if !yield(word) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,30 @@
+//go:build go1.23
+// +build go1.23
+
+package bits
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAll(t *testing.T) {
+	te := Trie{}
+	te.Init()
+	insertNotInAlphabeticalOrder(&te)
+	teData := te.Encode()
+	rd := CreateRankDirectory(teData, te.GetNodeCount()*2+1, L1, L2)
+	ft := FrozenTrie{}
+	ft.Init(teData, rd.GetData(), te.GetNodeCount())
+
+	var words []string
+	for word := range ft.All() {
+		words = append(words, word)
+		if len(words) == 3 {
+			break
+		}
+	}
+	if !reflect.DeepEqual(words, []string{"alphapha", "apple", "hello"}) {
+		t.Error("ft.All()", words)
+	}
+}
@@ -0,0 +1,51 @@
+package bits
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIterator(t *testing.T) {
+	te := Trie{}
+	te.Init()
+	insertNotInAlphabeticalOrder(&te)
+	te.Insert("app")
+	te.Insert("")
+	teData := te.Encode()
+	rd := CreateRankDirectory(teData, te.GetNodeCount()*2+1, L1, L2)
+	ft := FrozenTrie{}
+	ft.Init(teData, rd.GetData(), te.GetNodeCount())
+
+	var words []string
+	it := ft.Iter()
+	for word, ok := it.Next(); ok; word, ok = it.Next() {
+		words = append(words, word)
+	}
+	expected := []string{"", "alphapha", "app", "apple", "hello", "jello", "lamp", "orange", "quiz"}
+	if !reflect.DeepEqual(words, expected) {
+		t.Error("words not in alphabetical order", words)
+	}
+	if _, ok := it.Next(); ok {
+		t.Error("Next after the last word")
+	}
+
+	// the order of the alphabet, not of the code points
+	reversed := CreateAlphabet("zyxwvutsrqponmlkjihgfedcba ")
+	tr := Trie{}
+	tr.InitWithAlphabet(reversed)
+	insertInAlphabeticalOrder(&tr)
+	trData := tr.Encode()
+	rdr := CreateRankDirectory(trData, tr.GetNodeCount()*2+1, L1, L2)
+	fr := FrozenTrie{}
+	fr.InitWithAlphabet(trData, rdr.GetData(), tr.GetNodeCount(), reversed)
+
+	words = nil
+	it = fr.Iter()
+	for word, ok := it.Next(); ok; word, ok = it.Next() {
+		words = append(words, word)
+	}
+	expected = []string{"quiz", "orange", "lamp", "jello", "hello", "apple", "alphapha"}
+	if !reflect.DeepEqual(words, expected) {
+		t.Error("words not in the order of the alphabet", words)
+	}
+}
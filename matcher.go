@@ -0,0 +1,283 @@
+package bits
+
+/**
+ * Multi-pattern substring search (Aho-Corasick). Besides the unary encoding,
+ * every node has a failure link, the node of the longest proper suffix of its
+ * path which is also a path of the trie, and an output link, the nearest node
+ * on the chain of failure links which is a word. Both are kept in packed
+ * arrays indexed by node, with node index + 1 stored so that 0 means no link.
+ * The text is then read once: on a letter without a child, the failure links
+ * are followed until a node has one, and at every node the words ending there
+ * are reported by following the output links.
+ */
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"sort"
+	"unicode/utf8"
+)
+
+// ErrNoFailureLinks is returned by FrozenTrie.FindAll if the trie was not
+// saved with failure links.
+var ErrNoFailureLinks = errors.New("bits: trie has no failure links")
+
+/**
+  Set whether the failure links used by FrozenTrie.FindAll are written when
+  the trie is saved.
+*/
+func (t *Trie) SetBuildFailureLinks(build bool) {
+	t.buildFailureLinks = build
+}
+
+// writeFailureLinks writes the failure and output links of every node, in
+// level order, and returns them with the width of each link and the length
+// of the longest word.
+func (t *Trie) writeFailureLinks() (*BitWriter, *BitWriter, uint, uint) {
+	var nodes []*TrieNode
+	indexOf := map[*TrieNode]uint64{}
+	depth := map[*TrieNode]uint{t.root: 0}
+	var maxDepth uint = 0
+	t.Apply(func(node *TrieNode) {
+		indexOf[node] = uint64(len(nodes))
+		nodes = append(nodes, node)
+		for _, child := range node.children {
+			depth[child] = depth[node] + 1
+			if depth[child] > maxDepth {
+				maxDepth = depth[child]
+			}
+		}
+	})
+
+	fail := map[*TrieNode]*TrieNode{t.root: t.root}
+	out := map[*TrieNode]*TrieNode{}
+	// in level order, the failure link of a node is known before its
+	// children are visited.
+	for _, node := range nodes {
+		for _, child := range node.children {
+			f := t.root
+			if node != t.root {
+				for f = fail[node]; ; f = fail[f] {
+					if next := trieChild(f, child.letter); next != nil {
+						f = next
+						break
+					}
+					if f == t.root {
+						break
+					}
+				}
+			}
+			fail[child] = f
+			if f.final {
+				out[child] = f
+			} else {
+				out[child] = out[f]
+			}
+		}
+	}
+
+	failLinks := make([]uint64, len(nodes))
+	outLinks := make([]uint64, len(nodes))
+	for i, node := range nodes {
+		failLinks[i] = indexOf[fail[node]] + 1
+		if out[node] != nil {
+			outLinks[i] = indexOf[out[node]] + 1
+		}
+	}
+
+	// both arrays hold node indexes + 1
+	width := bitWidth(uint64(len(nodes)))
+	failBits := &BitWriter{}
+	outBits := &BitWriter{}
+	for i := range nodes {
+		writeUint64(failBits, failLinks[i], width)
+		writeUint64(outBits, outLinks[i], width)
+	}
+	return failBits, outBits, width, maxDepth
+}
+
+// trieChild returns the child of the node with the given letter, or nil.
+func trieChild(node *TrieNode, letter string) *TrieNode {
+	for _, child := range node.children {
+		if child.letter == letter {
+			return child
+		}
+	}
+	return nil
+}
+
+/**
+  Encode the failure links of the trie. Returns the encoded failure links,
+  the encoded output links, the number of bits of each link and the length
+  of the longest word, to be passed to FrozenTrie.SetFailureLinks.
+*/
+func (t *Trie) EncodeFailureLinks() (string, string, uint, uint) {
+	fail, out, width, maxDepth := t.writeFailureLinks()
+	return fail.GetData(), out.GetData(), width, maxDepth
+}
+
+/**
+  Set the failure links of the trie, as encoded by Trie.EncodeFailureLinks.
+*/
+func (f *FrozenTrie) SetFailureLinks(fail, out string, width, maxDepth uint) {
+	fbits := &BitString{}
+	fbits.Init(fail)
+	obits := &BitString{}
+	obits.Init(out)
+	f.setFailureLinks(fbits, obits, width, maxDepth)
+}
+
+func (f *FrozenTrie) setFailureLinks(fail, out BitVector, width, maxDepth uint) {
+	f.failLinks.init(fail, width, f.nodeCount)
+	f.outLinks.init(out, width, f.nodeCount)
+	f.maxDepth = maxDepth
+}
+
+// checkFailureLinks makes sure every link is a node of the trie which is
+// closer to the root than the node of the link, so that following the links
+// always ends at the root, that every output link is a word and that no word
+// is longer than the maximum depth. The failure link of the root is the root.
+func (f *FrozenTrie) checkFailureLinks() bool {
+	if f.failLinks.width == 0 || !f.failLinks.fits() || !f.outLinks.fits() {
+		return false
+	}
+	if f.failLinks.get(0) != 1 || f.outLinks.get(0) != 0 {
+		return false
+	}
+
+	starts := f.levelStarts()
+	if uint(len(starts))-2 > f.maxDepth {
+		return false
+	}
+	depthOf := func(index uint) int {
+		return sort.Search(len(starts), func(i int) bool { return starts[i] > index }) - 1
+	}
+
+	depth := 0
+	var i uint
+	for i = 1; i < f.nodeCount; i++ {
+		if i == starts[depth+1] {
+			depth++
+		}
+		fail := f.failLinks.get(i)
+		if fail == 0 || fail > uint64(f.nodeCount) || depthOf(uint(fail)-1) >= depth {
+			return false
+		}
+		out := f.outLinks.get(i)
+		if out > uint64(f.nodeCount) {
+			return false
+		}
+		if out != 0 && (depthOf(uint(out)-1) >= depth || !f.isFinal(uint(out)-1)) {
+			return false
+		}
+	}
+	return true
+}
+
+// levelStarts returns the index of the first node of every level, followed
+// by the node count. In level order, the nodes of a level are the children of
+// the nodes of the level above, so each level starts at the first child of
+// the first node of the level above.
+func (f *FrozenTrie) levelStarts() []uint {
+	starts := []uint{0}
+	for start := uint(0); start < f.nodeCount; {
+		start = f.directory.Select(0, start+1) - start
+		starts = append(starts, start)
+	}
+	return starts
+}
+
+/**
+  A word found by FrozenTrie.FindAll: the offset in bytes of its first
+  letter in the text, and its ID (see FrozenTrie.Index).
+*/
+type Match struct {
+	Offset int64
+	WordID uint
+}
+
+/**
+  Read the text from r and call fn for every occurrence of every word of the
+  trie but the empty word, in a single pass. The words ending at the same letter are reported
+  the longest first. Reading stops when fn returns false. The trie must have
+  been saved with failure links (see Trie.SetBuildFailureLinks), otherwise
+  ErrNoFailureLinks is returned. The text is not normalized: if the trie has
+  normalizers, it must already be in the normal form of the words, so that
+  the offsets are offsets in the text read.
+*/
+func (f *FrozenTrie) FindAll(r io.Reader, fn func(Match) bool) error {
+	if f.failLinks.width == 0 {
+		return ErrNoFailureLinks
+	}
+
+	br := bufio.NewReader(r)
+	// the offsets of the last maxDepth letters read, to find where a word
+	// starts from its length
+	starts := make([]int64, f.maxDepth+1)
+	var offset, count int64 = 0, 0
+
+	node := f.GetRoot()
+	for {
+		runeValue, width, err := br.ReadRune()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		starts[count%int64(len(starts))] = offset
+		count++
+		offset += int64(width)
+
+		letter := string(runeValue)
+		if runeValue == utf8.RuneError && width == 1 {
+			letter = ""
+		}
+		for {
+			child, ok := f.findChild(node, letter)
+			if ok {
+				node = child
+				break
+			}
+			if node.index == 0 {
+				break
+			}
+			node = f.GetNodeByIndex(uint(f.failLinks.get(node.index)) - 1)
+		}
+
+		index := node.index
+		if !node.final {
+			index = uint(f.outLinks.get(index))
+			if index == 0 {
+				continue
+			}
+			index--
+		}
+		// the empty word, at the root, does not occur in the text
+		for index != 0 {
+			depth := int64(f.depth(index))
+			m := Match{
+				Offset: starts[(count-depth)%int64(len(starts))],
+				WordID: f.wordID(index),
+			}
+			if !fn(m) {
+				return nil
+			}
+			next := f.outLinks.get(index)
+			if next == 0 {
+				break
+			}
+			index = uint(next) - 1
+		}
+	}
+}
+
+// depth returns the number of letters on the path to the node.
+func (f *FrozenTrie) depth(index uint) uint {
+	var depth uint = 0
+	for ; index != 0; index = f.getParent(index) {
+		depth++
+	}
+	return depth
+}
@@ -0,0 +1,143 @@
+package bits
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFindAll(t *testing.T) {
+	te := Trie{}
+	te.Init()
+	te.Insert("he")
+	te.Insert("she")
+	te.Insert("his")
+	te.Insert("hers")
+	te.SetBuildFailureLinks(true)
+
+	var buf bytes.Buffer
+	if err := te.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	ft, err := Load(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type found struct {
+		offset int64
+		word   string
+	}
+	var result []found
+	err = ft.FindAll(strings.NewReader("ushers, his ships"), func(m Match) bool {
+		word, _ := ft.Word(m.WordID)
+		result = append(result, found{m.Offset, word})
+		return true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []found{{1, "she"}, {2, "he"}, {2, "hers"}, {8, "his"}}
+	if !reflect.DeepEqual(result, expected) {
+		t.Error("FindAll", result)
+	}
+
+	// the offsets are in bytes
+	te2 := Trie{}
+	te2.InitWithAlphabet(CreateAlphabet("abcdeghijklmnoprstuvyāīūṁṃŋṇṅñṭḍḷ…'’° -"))
+	te2.Insert("ṃa")
+	te2.SetBuildFailureLinks(true)
+	buf.Reset()
+	if err := te2.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	ft2, err := Load(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var offsets []int64
+	ft2.FindAll(strings.NewReader("āṃaṃṃa"), func(m Match) bool {
+		offsets = append(offsets, m.Offset)
+		return true
+	})
+	if !reflect.DeepEqual(offsets, []int64{2, 9}) {
+		t.Error("offsets", offsets)
+	}
+
+	// the empty word is not found at every letter
+	te3 := Trie{}
+	te3.Init()
+	te3.Insert("")
+	te3.Insert("a")
+	te3.SetBuildFailureLinks(true)
+	buf.Reset()
+	if err := te3.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	ft3, err := Load(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var matches []Match
+	ft3.FindAll(strings.NewReader("bab"), func(m Match) bool {
+		matches = append(matches, m)
+		return true
+	})
+	if id, _ := ft3.Index("a"); !reflect.DeepEqual(matches, []Match{{1, id}}) {
+		t.Error("FindAll with the empty word", matches)
+	}
+
+	te.SetBuildFailureLinks(false)
+	buf.Reset()
+	te.Save(&buf)
+	ft, _ = Load(&buf)
+	if err := ft.FindAll(strings.NewReader("she"), func(Match) bool { return true }); err != ErrNoFailureLinks {
+		t.Error("expected ErrNoFailureLinks, got ", err)
+	}
+}
+
+func TestLoadFailureLinkCycle(t *testing.T) {
+	te := Trie{}
+	te.Init()
+	te.Insert("ab")
+	data, directoryData := te.appendTrieBits()
+
+	blob := func(failLinks []uint64) []byte {
+		fail := &BitWriter{}
+		out := &BitWriter{}
+		for _, link := range failLinks {
+			writeUint64(fail, link, 2)
+			writeUint64(out, 0, 2)
+		}
+		links := appendUvarint(nil, 2)
+		links = appendUvarint(links, 2)
+		links = appendBits(links, fail, StorageBase64)
+		links = appendBits(links, out, StorageBase64)
+
+		buf := []byte(containerMagic)
+		buf = appendUvarint(buf, containerVersion)
+		buf = appendSection(buf, sectionAlphabet, appendString(nil, allowedCharacters))
+		buf = appendSection(buf, sectionDirectorySizes, appendUvarint(appendUvarint(nil, uint64(L1)), uint64(L2)))
+		buf = appendSection(buf, sectionNodeCount, appendUvarint(nil, uint64(te.GetNodeCount())))
+		buf = appendSection(buf, sectionTrieData, data)
+		buf = appendSection(buf, sectionDirectoryData, directoryData)
+		buf = appendSection(buf, sectionFailureLinks, links)
+		buf = appendUvarint(buf, sectionEnd)
+		return appendChecksum(buf)
+	}
+
+	if _, err := Load(bytes.NewReader(blob([]uint64{1, 1, 1}))); err != nil {
+		t.Error(err)
+	}
+
+	// the failure link of "a" is "a" itself, which FindAll would follow
+	// forever on "ac"
+	if _, err := Load(bytes.NewReader(blob([]uint64{1, 2, 1}))); err != ErrDataMismatch {
+		t.Error("expected ErrDataMismatch, got ", err)
+	}
+	// the failure link of the root is not the root
+	if _, err := Load(bytes.NewReader(blob([]uint64{2, 1, 1}))); err != ErrDataMismatch {
+		t.Error("expected ErrDataMismatch, got ", err)
+	}
+}
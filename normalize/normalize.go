@@ -0,0 +1,54 @@
+// Package normalize registers the Unicode normalizers of the succinct trie.
+// Import it for its side effect:
+//
+//	import _ "github.com/siongui/go-succinct-data-structure-trie/normalize"
+//
+// The normalizers are "nfc", "nfd", "nfkc" and "nfkd", the Unicode
+// normalization forms, and "strip-diacritics", which removes the combining
+// marks, so that for example "ā" and "ṃ" become "a" and "m". CreateFoldingMap
+// uses it to fold the letters of an alphabet for FrozenTrie.SuggestFolded.
+package normalize
+
+import (
+	"strings"
+	"unicode"
+
+	bits "github.com/siongui/go-succinct-data-structure-trie"
+	"golang.org/x/text/unicode/norm"
+)
+
+func init() {
+	bits.RegisterNormalizer("nfc", norm.NFC.String)
+	bits.RegisterNormalizer("nfd", norm.NFD.String)
+	bits.RegisterNormalizer("nfkc", norm.NFKC.String)
+	bits.RegisterNormalizer("nfkd", norm.NFKD.String)
+	bits.RegisterNormalizer("strip-diacritics", StripDiacritics)
+}
+
+/**
+  Returns the word without its combining marks, in NFC form.
+*/
+func StripDiacritics(word string) string {
+	word = strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.Mn, r) {
+			return -1
+		}
+		return r
+	}, norm.NFD.String(word))
+	return norm.NFC.String(word)
+}
+
+/**
+  Create the folding map of the letters of the alphabet to their letters
+  without diacritics, for FrozenTrie.SuggestFolded.
+*/
+func CreateFoldingMap(alphabet *bits.Alphabet) *bits.FoldingMap {
+	m := bits.CreateFoldingMap()
+	for _, r := range alphabet.GetCharacters() {
+		letter := string(r)
+		if base := StripDiacritics(letter); base != letter && base != "" {
+			m.Set(letter, base)
+		}
+	}
+	return m
+}
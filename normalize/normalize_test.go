@@ -0,0 +1,96 @@
+package normalize
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	bits "github.com/siongui/go-succinct-data-structure-trie"
+)
+
+func TestNormalizers(t *testing.T) {
+	// "ā" and "ṃ" composed, and decomposed
+	nfc := "dhammaṃ ā"
+	nfd := "dhammaṃ ā"
+
+	te := bits.Trie{}
+	te.InitWithDerivedAlphabet(bits.OrderByCodePoint)
+	if err := te.SetNormalizers("nfc", "casefold"); err != nil {
+		t.Fatal(err)
+	}
+	te.Insert(nfd)
+	te.Insert("Buddha")
+
+	var buf bytes.Buffer
+	if err := te.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	ft, err := bits.Load(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ft.GetNormalizers(), []string{"nfc", "casefold"}) {
+		t.Error("GetNormalizers", ft.GetNormalizers())
+	}
+	for _, word := range []string{nfc, nfd, "buddha", "BUDDHA"} {
+		if !ft.Lookup(word) {
+			t.Errorf("Lookup %q", word)
+		}
+	}
+	if words := ft.GetSuggestedWords("Dhammaṃ", 10); !reflect.DeepEqual(words, []string{nfc}) {
+		t.Errorf("GetSuggestedWords %q", words)
+	}
+
+	td := bits.CreateTrieData(&te)
+	ft2, err := td.CreateFrozenTrie(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ft2.Lookup(nfd) {
+		t.Error("CreateFrozenTrie")
+	}
+}
+
+func TestStripDiacritics(t *testing.T) {
+	te := bits.Trie{}
+	te.Init()
+	if err := te.SetNormalizers("strip-diacritics"); err != nil {
+		t.Fatal(err)
+	}
+	if err := te.Insert("saṅghaṃ"); err != nil {
+		t.Fatal(err)
+	}
+	td := bits.CreateTrieData(&te)
+	ft, err := td.CreateFrozenTrie(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ft.Lookup("sangham") || !ft.Lookup("saṅghaṃ") {
+		t.Error("Lookup")
+	}
+}
+
+func TestCreateFoldingMap(t *testing.T) {
+	alphabet := bits.CreateAlphabet("abcdeghijklmnoprstuvyāīūṁṃŋṇṅñṭḍḷ…'’° -")
+	m := CreateFoldingMap(alphabet)
+	for letter, base := range map[string]string{"ā": "a", "ṃ": "m", "ṅ": "n", "ḷ": "l", "ŋ": "ŋ", "a": "a"} {
+		if m.Fold(letter) != base {
+			t.Errorf("Fold %q: %q", letter, m.Fold(letter))
+		}
+	}
+
+	te := bits.Trie{}
+	te.InitWithAlphabet(alphabet)
+	te.Insert("dhamma")
+	te.Insert("dhammaṃ")
+	te.Insert("dhammā")
+	td := bits.CreateTrieData(&te)
+	ft, err := td.CreateFrozenTrie(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ft.SetFoldingMap(m)
+	if words := ft.SuggestFolded("dhamma", 10); !reflect.DeepEqual(words, []string{"dhamma", "dhammaṃ", "dhammā"}) {
+		t.Errorf("SuggestFolded %q", words)
+	}
+}
@@ -0,0 +1,145 @@
+package bits
+
+/**
+ * Normalization of the keys. A trie can be given normalizers, by name, which
+ * are applied in order to the words by Insert and to the words and prefixes
+ * looked up by FrozenTrie, so that different spellings of the same word, for
+ * example in NFC and NFD forms, are found. The names are written by Save and
+ * CreateTrieData and the FrozenTrie applies the same normalizers when loaded.
+ * Wildcard patterns, regular expressions and the text read by FindAll are not
+ * normalized, they must be written in the normal form of the words.
+ *
+ * The "casefold" normalizer is always available. Importing the package
+ * github.com/siongui/go-succinct-data-structure-trie/normalize registers the
+ * Unicode normalization forms and diacritic stripping.
+ */
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+/**
+  A Normalizer returns the normal form of a word.
+*/
+type Normalizer func(word string) string
+
+// ErrUnknownNormalizer is returned for normalizers which are not registered.
+var ErrUnknownNormalizer = errors.New("bits: unknown normalizer")
+
+// ErrNormalizersAfterInsert is returned by Trie.SetNormalizers once words are
+// inserted, which were not normalized.
+var ErrNormalizersAfterInsert = errors.New("bits: normalizers set after inserting words")
+
+var (
+	normalizersMu sync.RWMutex
+	normalizers   = map[string]Normalizer{
+		"casefold": foldCase,
+	}
+)
+
+// foldCase maps every letter to the lower case of its upper case, so that
+// for example "ς" and "σ" are the same.
+func foldCase(word string) string {
+	return strings.Map(func(r rune) rune {
+		return unicode.ToLower(unicode.ToUpper(r))
+	}, word)
+}
+
+/**
+  Register a normalizer under the given name. It panics if the name is
+  already registered or the normalizer is nil.
+*/
+func RegisterNormalizer(name string, normalizer Normalizer) {
+	normalizersMu.Lock()
+	defer normalizersMu.Unlock()
+	if normalizer == nil {
+		panic("bits: RegisterNormalizer normalizer is nil")
+	}
+	if _, ok := normalizers[name]; ok {
+		panic("bits: RegisterNormalizer called twice for " + name)
+	}
+	normalizers[name] = normalizer
+}
+
+// composeNormalizers returns the normalizer applying the named normalizers
+// in order, or nil if there are none.
+func composeNormalizers(names []string) (Normalizer, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	normalizersMu.RLock()
+	defer normalizersMu.RUnlock()
+
+	var list []Normalizer
+	for _, name := range names {
+		normalizer, ok := normalizers[name]
+		if !ok {
+			return nil, ErrUnknownNormalizer
+		}
+		list = append(list, normalizer)
+	}
+	return func(word string) string {
+		for _, normalizer := range list {
+			word = normalizer(word)
+		}
+		return word
+	}, nil
+}
+
+/**
+  Set the normalizers applied to the inserted words, by name, in order. They
+  must be set before inserting words, ErrNormalizersAfterInsert is returned
+  otherwise. ErrUnknownNormalizer is returned if a name is not registered.
+*/
+func (t *Trie) SetNormalizers(names ...string) error {
+	if t.nodeCount > 1 {
+		return ErrNormalizersAfterInsert
+	}
+	normalize, err := composeNormalizers(names)
+	if err != nil {
+		return err
+	}
+	t.normalizers = append([]string(nil), names...)
+	t.normalize = normalize
+	return nil
+}
+
+/**
+  Returns the names of the normalizers of the trie.
+*/
+func (t *Trie) GetNormalizers() []string {
+	return t.normalizers
+}
+
+/**
+  Set the normalizers applied to the words looked up, which must be the ones
+  the trie was built with. Load sets them from the container. See
+  Trie.SetNormalizers.
+*/
+func (f *FrozenTrie) SetNormalizers(names ...string) error {
+	normalize, err := composeNormalizers(names)
+	if err != nil {
+		return err
+	}
+	f.normalizers = append([]string(nil), names...)
+	f.normalize = normalize
+	return nil
+}
+
+/**
+  Returns the names of the normalizers of the trie.
+*/
+func (f *FrozenTrie) GetNormalizers() []string {
+	return f.normalizers
+}
+
+// normalizeWord returns the normal form of a word looked up.
+func (f *FrozenTrie) normalizeWord(word string) string {
+	if f.normalize == nil {
+		return word
+	}
+	return f.normalize(word)
+}
@@ -0,0 +1,81 @@
+package bits
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCaseFold(t *testing.T) {
+	te := Trie{}
+	te.Init()
+	if err := te.SetNormalizers("casefold"); err != nil {
+		t.Fatal(err)
+	}
+	te.Insert("Hello")
+	te.Insert("WORLD")
+
+	var buf bytes.Buffer
+	if err := te.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	ft, err := Load(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, word := range []string{"hello", "HELLO", "World"} {
+		if !ft.Lookup(word) {
+			t.Error("Lookup", word)
+		}
+	}
+	if id, ok := ft.Index("WoRlD"); !ok || id != 1 {
+		t.Error("Index", id, ok)
+	}
+	if words := ft.GetSuggestedWords("HE", 10); len(words) != 1 || words[0] != "hello" {
+		t.Error("GetSuggestedWords", words)
+	}
+	if matches := ft.FuzzySearch("Helo", 1, 10); len(matches) != 1 || matches[0].Word != "hello" {
+		t.Error("FuzzySearch", matches)
+	}
+	if word, n, ok := ft.LongestPrefix("HELLOworld"); !ok || word != "HELLO" || n != 5 {
+		t.Error("LongestPrefix", word, n, ok)
+	}
+	if words := ft.Segment("helloWorld", 10); len(words) != 1 || len(words[0]) != 2 {
+		t.Error("Segment", words)
+	}
+}
+
+func TestNormalizersAfterInsert(t *testing.T) {
+	te := Trie{}
+	te.Init()
+	te.Insert("hello")
+	if err := te.SetNormalizers("casefold"); err != ErrNormalizersAfterInsert {
+		t.Error("SetNormalizers", err)
+	}
+	if te.GetNormalizers() != nil {
+		t.Error("normalizers set", te.GetNormalizers())
+	}
+}
+
+func TestUnknownNormalizer(t *testing.T) {
+	te := Trie{}
+	te.Init()
+	if err := te.SetNormalizers("casefold", "no-such-normalizer"); err != ErrUnknownNormalizer {
+		t.Error("SetNormalizers", err)
+	}
+
+	RegisterNormalizer("test-upper-to-lower", foldCase)
+	if err := te.SetNormalizers("test-upper-to-lower"); err != nil {
+		t.Fatal(err)
+	}
+	te.Insert("a")
+	var buf bytes.Buffer
+	te.Save(&buf)
+
+	// a loader which does not know the normalizer
+	normalizersMu.Lock()
+	delete(normalizers, "test-upper-to-lower")
+	normalizersMu.Unlock()
+	if _, err := Load(&buf); err != ErrUnknownNormalizer {
+		t.Error("Load", err)
+	}
+}
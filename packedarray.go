@@ -0,0 +1,80 @@
+package bits
+
+/**
+ * A packed array stores unsigned integers using the same number of bits for
+ * each of them, enough for the largest one.
+ */
+
+// packedArray reads the integers written by writePackedArray.
+type packedArray struct {
+	bits   BitVector
+	width  uint
+	length uint
+}
+
+// writePackedArray writes the values to a BitWriter and returns it with the
+// number of bits used for each value.
+func writePackedArray(values []uint64) (*BitWriter, uint) {
+	var max uint64 = 0
+	for _, value := range values {
+		if value > max {
+			max = value
+		}
+	}
+	width := bitWidth(max)
+
+	bw := &BitWriter{}
+	for _, value := range values {
+		writeUint64(bw, value, width)
+	}
+	return bw, width
+}
+
+// bitWidth returns the number of bits needed to write the value.
+func bitWidth(value uint64) uint {
+	var width uint = 0
+	for ; width < 64 && value>>width != 0; width++ {
+	}
+	return width
+}
+
+// writeUint64 writes the lowest n bits of value, at most 32 at a time, as
+// Write and Get work on uint.
+func writeUint64(bw *BitWriter, value uint64, n uint) {
+	if n > 32 {
+		bw.Write(uint(value>>32), n-32)
+		n = 32
+	}
+	bw.Write(uint(value&0xffffffff), n)
+}
+
+// readUint64 reads the n bits written by writeUint64 at position p.
+func readUint64(bits BitVector, p, n uint) uint64 {
+	var value uint64 = 0
+	if n > 32 {
+		value = uint64(bits.Get(p, n-32)) << 32
+		p += n - 32
+		n = 32
+	}
+	return value | uint64(bits.Get(p, n))
+}
+
+// init makes the array read length values of the given width from bits.
+func (pa *packedArray) init(bits BitVector, width, length uint) {
+	pa.bits = bits
+	pa.width = width
+	pa.length = length
+}
+
+// fits returns true if the bits hold all the values.
+func (pa *packedArray) fits() bool {
+	return pa.width == 0 || (pa.bits != nil && pa.bits.Length() >= pa.width*pa.length)
+}
+
+// get returns the i'th value, or 0 if the array is empty.
+func (pa *packedArray) get(i uint) uint64 {
+	if pa.bits == nil || pa.width == 0 {
+		return 0
+	}
+	return readUint64(pa.bits, i*pa.width, pa.width)
+}
@@ -0,0 +1,288 @@
+package bits
+
+/**
+ * Path-compressed (Patricia) variant of the succinct trie. Every chain of
+ * nodes which are not words and have a single child is collapsed into the
+ * edge above the node it ends at, so the trie has one node per word or
+ * branch. The nodes are encoded as by Trie.Encode, the data of a node holding
+ * the first letter of its edge, and the other letters of the edges are kept
+ * in a separate label array:
+ *
+ *   offset width (7 bits) | nodeCount+1 offsets | letter codes
+ *
+ * The letters of the edge of node i, after the first one, are the codes from
+ * offsets[i] to offsets[i+1]. Every code takes dataBits-1 bits.
+ */
+
+import "unicode/utf8"
+
+const patriciaOffsetWidthBits = 7
+
+// patriciaNode is a node of the path-compressed trie: the last node of a
+// chain, and the letters of the chain.
+type patriciaNode struct {
+	node    *TrieNode
+	letters []string
+}
+
+// patriciaNodes returns the nodes of the path-compressed trie in level order.
+func (t *Trie) patriciaNodes() []patriciaNode {
+	// sort the children of all the nodes
+	t.Apply(func(node *TrieNode) {})
+
+	nodes := []patriciaNode{{node: t.root, letters: []string{t.root.letter}}}
+	for i := 0; i < len(nodes); i++ {
+		for _, child := range nodes[i].node.children {
+			pn := patriciaNode{node: child, letters: []string{child.letter}}
+			for !pn.node.final && len(pn.node.children) == 1 {
+				pn.node = pn.node.children[0]
+				pn.letters = append(pn.letters, pn.node.letter)
+			}
+			nodes = append(nodes, pn)
+		}
+	}
+	return nodes
+}
+
+/**
+  Encode the trie with path compression. Returns the encoded nodes, the
+  encoded label array and the number of nodes, to be passed to
+  PatriciaTrie.Init together with the rank directory of the encoded nodes.
+*/
+func (t *Trie) EncodePatricia() (string, string, uint) {
+	data, labels, nodeCount := t.encodePatriciaBits()
+	return data.GetData(), labels.GetData(), nodeCount
+}
+
+func (t *Trie) encodePatriciaBits() (*BitWriter, *BitWriter, uint) {
+	nodes := t.patriciaNodes()
+	dataBits := t.GetAlphabet().GetDataBits()
+
+	data := &BitWriter{}
+	data.Write(0x02, 2)
+	for _, pn := range nodes {
+		for i := 0; i < len(pn.node.children); i++ {
+			data.Write(1, 1)
+		}
+		data.Write(0, 1)
+	}
+
+	var offsets []uint64
+	var codes []uint
+	for _, pn := range nodes {
+		value, _ := t.GetAlphabet().encode(pn.letters[0])
+		if pn.node.final {
+			value |= 1 << (dataBits - 1)
+		}
+		data.Write(value, dataBits)
+
+		offsets = append(offsets, uint64(len(codes)))
+		for _, letter := range pn.letters[1:] {
+			code, _ := t.GetAlphabet().encode(letter)
+			codes = append(codes, code)
+		}
+	}
+	offsets = append(offsets, uint64(len(codes)))
+
+	labels := &BitWriter{}
+	width := bitWidth(uint64(len(codes)))
+	labels.Write(width, patriciaOffsetWidthBits)
+	for _, offset := range offsets {
+		writeUint64(labels, offset, width)
+	}
+	for _, code := range codes {
+		labels.Write(code, dataBits-1)
+	}
+	return data, labels, uint(len(nodes))
+}
+
+/**
+  The sizes of the trie encoded by Encode and by EncodePatricia, as returned
+  by Trie.GetPatriciaSize. The bits include the rank directory, and for the
+  path-compressed trie, the label array.
+*/
+type PatriciaSize struct {
+	NodeCount         uint
+	Bits              uint
+	PatriciaNodeCount uint
+	PatriciaBits      uint
+}
+
+/**
+  Encode the trie with and without path compression and return the sizes of
+  both encodings.
+*/
+func (t *Trie) GetPatriciaSize() PatriciaSize {
+	numBits := t.GetNodeCount()*2 + 1
+	rd := CreateBinaryRankDirectory(t.EncodeBinary(), numBits, L1, L2)
+
+	data, labels, nodeCount := t.encodePatriciaBits()
+	prd := CreateBinaryRankDirectory(data.GetWords(), nodeCount*2+1, L1, L2)
+
+	return PatriciaSize{
+		NodeCount:         t.GetNodeCount(),
+		Bits:              numBits + t.GetNodeCount()*t.GetAlphabet().GetDataBits() + rd.rankBits(),
+		PatriciaNodeCount: nodeCount,
+		PatriciaBits:      nodeCount*2 + 1 + nodeCount*t.GetAlphabet().GetDataBits() + prd.rankBits() + labels.length,
+	}
+}
+
+/**
+  The PatriciaTrie is used for looking up words in a trie encoded by
+  Trie.EncodePatricia.
+*/
+type PatriciaTrie struct {
+	trie    FrozenTrie
+	offsets packedArray
+	codes   packedArray
+}
+
+func (p *PatriciaTrie) Init(data, directoryData, labels string, nodeCount uint) {
+	p.InitWithAlphabet(data, directoryData, labels, nodeCount, defaultAlphabet)
+}
+
+func (p *PatriciaTrie) InitWithAlphabet(data, directoryData, labels string, nodeCount uint, alphabet *Alphabet) {
+	p.trie.InitWithAlphabet(data, directoryData, nodeCount, alphabet)
+	p.trie.SetSortedChildren(true)
+
+	bits := &BitString{}
+	bits.Init(labels)
+	width := bits.Get(0, patriciaOffsetWidthBits)
+	p.offsets.init(&offsetBitVector{bits, patriciaOffsetWidthBits}, width, nodeCount+1)
+	start := patriciaOffsetWidthBits + width*(nodeCount+1)
+	p.codes.init(&offsetBitVector{bits, start}, alphabet.GetDataBits()-1, uint(p.offsets.get(nodeCount)))
+}
+
+// offsetBitVector is the part of a BitVector after the first start bits.
+type offsetBitVector struct {
+	bits  BitVector
+	start uint
+}
+
+func (o *offsetBitVector) Get(p, n uint) uint {
+	return o.bits.Get(o.start+p, n)
+}
+
+func (o *offsetBitVector) Count(p, n uint) uint {
+	return o.bits.Count(o.start+p, n)
+}
+
+func (o *offsetBitVector) Rank(x uint) uint {
+	return o.Count(0, x+1)
+}
+
+func (o *offsetBitVector) Length() uint {
+	if o.bits.Length() < o.start {
+		return 0
+	}
+	return o.bits.Length() - o.start
+}
+
+/**
+  Returns the number of nodes in the path-compressed trie.
+*/
+func (p *PatriciaTrie) GetNodeCount() uint {
+	return p.trie.GetNodeCount()
+}
+
+// label returns the letters of the edge of the node after the first one.
+func (p *PatriciaTrie) label(index uint) []uint {
+	start, end := uint(p.offsets.get(index)), uint(p.offsets.get(index+1))
+	codes := make([]uint, 0, end-start)
+	for i := start; i < end; i++ {
+		codes = append(codes, uint(p.codes.get(i)))
+	}
+	return codes
+}
+
+// spellLabel returns the letters of the edge of the node.
+func (p *PatriciaTrie) spellLabel(index uint) string {
+	letters := p.trie.getLetter(index)
+	for _, code := range p.label(index) {
+		letter, _ := p.trie.alphabet.decode(code)
+		letters += letter
+	}
+	return letters
+}
+
+// findNode follows the edges spelled by word. It returns the node of the
+// edge where word ends, the letters of the edge after the end of word, and
+// true if word is a path of the trie.
+func (p *PatriciaTrie) findNode(word string) (FrozenTrieNode, string, bool) {
+	node := p.trie.GetRoot()
+	for i := 0; i < len(word); {
+		runeValue, width := utf8.DecodeRuneInString(word[i:])
+		i += width
+		child, ok := p.trie.findChild(node, string(runeValue))
+		if !ok {
+			return node, "", false
+		}
+		node = child
+
+		codes := p.label(node.index)
+		for j, code := range codes {
+			if i == len(word) {
+				rest := ""
+				for _, c := range codes[j:] {
+					letter, _ := p.trie.alphabet.decode(c)
+					rest += letter
+				}
+				return node, rest, true
+			}
+			runeValue, width = utf8.DecodeRuneInString(word[i:])
+			i += width
+			if c, ok := p.trie.alphabet.encode(string(runeValue)); !ok || c != code {
+				return node, "", false
+			}
+		}
+	}
+	return node, "", true
+}
+
+/**
+  Look-up a word in the trie. Returns true if and only if the word exists
+  in the trie.
+*/
+func (p *PatriciaTrie) Lookup(word string) bool {
+	node, rest, ok := p.findNode(word)
+	return ok && rest == "" && node.final
+}
+
+/**
+  Given a word, returns at most limit words of the trie, prefix of which is
+  word, in level order of the path-compressed trie.
+*/
+func (p *PatriciaTrie) GetSuggestedWords(word string, limit int) []string {
+	var result []string
+	if limit <= 0 {
+		return result
+	}
+	node, rest, ok := p.findNode(word)
+	if !ok {
+		return result
+	}
+
+	level := []FrozenTrieNode{node}
+	prefixLevel := []string{word + rest}
+	for len(level) > 0 {
+		nodeNow := level[0]
+		level = level[1:]
+		prefixNow := prefixLevel[0]
+		prefixLevel = prefixLevel[1:]
+
+		if nodeNow.final {
+			result = append(result, prefixNow)
+			if len(result) == limit {
+				return result
+			}
+		}
+
+		var i uint = 0
+		for ; i < nodeNow.GetChildCount(); i++ {
+			child := nodeNow.GetChild(i)
+			level = append(level, child)
+			prefixLevel = append(prefixLevel, prefixNow+p.spellLabel(child.index))
+		}
+	}
+	return result
+}
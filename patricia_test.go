@@ -0,0 +1,60 @@
+package bits
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestPatriciaTrie(t *testing.T) {
+	alphabet := CreateAlphabet("abcdeghijklmnoprstuvyāīūṁṃŋṇṅñṭḍḷ…'’° -")
+	words := []string{"", "ariya", "ariyasacca", "buddha", "buddhaṃ", "dhamma",
+		"dhammacakkappavattana", "dhammaṃ", "sacca", "saccavācā"}
+
+	te := Trie{}
+	te.InitWithAlphabet(alphabet)
+	for _, word := range words {
+		te.Insert(word)
+	}
+
+	data, labels, nodeCount := te.EncodePatricia()
+	rd := CreateRankDirectory(data, nodeCount*2+1, L1, L2)
+	pt := PatriciaTrie{}
+	pt.InitWithAlphabet(data, rd.GetData(), labels, nodeCount, alphabet)
+
+	ft := FrozenTrie{}
+	rd2 := CreateRankDirectory(te.Encode(), te.GetNodeCount()*2+1, L1, L2)
+	ft.InitWithAlphabet(te.Encode(), rd2.GetData(), te.GetNodeCount(), alphabet)
+
+	// one node per word and per branch
+	if pt.GetNodeCount() != 10 {
+		t.Error("GetNodeCount", pt.GetNodeCount())
+	}
+
+	for _, word := range append(words, "a", "ariyas", "dhammac", "sa", "buddhaa", "x", "saccav") {
+		if pt.Lookup(word) != ft.Lookup(word) {
+			t.Error("Lookup", word, pt.Lookup(word))
+		}
+	}
+
+	for _, prefix := range []string{"", "a", "ariyasa", "buddh", "dhamma", "s", "x", "dhammaṃx"} {
+		result := pt.GetSuggestedWords(prefix, 100)
+		expected := ft.GetSuggestedWords(prefix, 100)
+		sort.Strings(result)
+		sort.Strings(expected)
+		if !reflect.DeepEqual(result, expected) {
+			t.Error("GetSuggestedWords", prefix, result, expected)
+		}
+	}
+	if result := pt.GetSuggestedWords("dha", 2); !reflect.DeepEqual(result, []string{"dhamma", "dhammacakkappavattana"}) {
+		t.Error("GetSuggestedWords limit", result)
+	}
+
+	size := te.GetPatriciaSize()
+	if size.NodeCount != te.GetNodeCount() || size.PatriciaNodeCount != nodeCount {
+		t.Error("GetPatriciaSize node counts", size)
+	}
+	if size.PatriciaBits >= size.Bits {
+		t.Error("GetPatriciaSize bits", size)
+	}
+}
@@ -0,0 +1,64 @@
+package bits
+
+/**
+ * Prefix matching for tokenization: the words of the trie which are
+ * prefixes of a text are the final nodes on the path spelled by the text,
+ * so a single walk from the root finds all of them.
+ */
+
+import "unicode/utf8"
+
+// walkPrefixes follows the text from the root and calls fn with the length
+// in bytes of every prefix of the text which is a word, and the index of the
+// final node of the word, the shortest first. The walk stops when fn returns
+// false. If the trie has normalizers, every letter of the text is normalized
+// on its own, so that the lengths are still lengths in the text. Normalizers
+// which combine letters, such as NFC, are therefore only applied within a
+// letter.
+func (f *FrozenTrie) walkPrefixes(text string, fn func(n int, index uint) bool) {
+	node := f.GetRoot()
+	if node.final && !fn(0, node.index) {
+		return
+	}
+
+	for i, w := 0, 0; i < len(text); i += w {
+		runeValue, width := utf8.DecodeRuneInString(text[i:])
+		w = width
+		for _, r := range f.normalizeWord(string(runeValue)) {
+			child, ok := f.findChild(node, string(r))
+			if !ok {
+				return
+			}
+			node = child
+		}
+		if node.final && !fn(i+w, node.index) {
+			return
+		}
+	}
+}
+
+/**
+  Returns the longest word of the trie which is a prefix of text, its length
+  in bytes, and true if there is one. The word is returned as it is spelled
+  in text, which is normalized letter by letter if the trie has normalizers.
+*/
+func (f *FrozenTrie) LongestPrefix(text string) (word string, n int, ok bool) {
+	f.walkPrefixes(text, func(end int, index uint) bool {
+		n, ok = end, true
+		return true
+	})
+	return text[:n], n, ok
+}
+
+/**
+  Returns all the words of the trie which are prefixes of text, the shortest
+  first, as they are spelled in text. See LongestPrefix.
+*/
+func (f *FrozenTrie) AllPrefixes(text string) []string {
+	var result []string
+	f.walkPrefixes(text, func(end int, index uint) bool {
+		result = append(result, text[:end])
+		return true
+	})
+	return result
+}
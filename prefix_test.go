@@ -0,0 +1,39 @@
+package bits
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPrefixes(t *testing.T) {
+	te := Trie{}
+	te.Init()
+	te.Insert("a")
+	te.Insert("an")
+	te.Insert("and")
+	te.Insert("android")
+	te.Insert("b")
+	teData := te.Encode()
+	rd := CreateRankDirectory(teData, te.GetNodeCount()*2+1, L1, L2)
+	ft := FrozenTrie{}
+	ft.Init(teData, rd.GetData(), te.GetNodeCount())
+
+	word, n, ok := ft.LongestPrefix("andromeda")
+	if word != "and" || n != 3 || !ok {
+		t.Error(`LongestPrefix("andromeda")`, word, n, ok)
+	}
+	word, n, ok = ft.LongestPrefix("android phone")
+	if word != "android" || n != 7 || !ok {
+		t.Error(`LongestPrefix("android phone")`, word, n, ok)
+	}
+	if _, _, ok := ft.LongestPrefix("cat"); ok {
+		t.Error(`LongestPrefix("cat")`)
+	}
+
+	if !reflect.DeepEqual(ft.AllPrefixes("andromeda"), []string{"a", "an", "and"}) {
+		t.Error(`AllPrefixes("andromeda")`, ft.AllPrefixes("andromeda"))
+	}
+	if len(ft.AllPrefixes("cat")) != 0 {
+		t.Error(`AllPrefixes("cat")`)
+	}
+}
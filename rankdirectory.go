@@ -14,14 +14,19 @@ var L2 uint = 32
   string.
 */
 type RankDirectory struct {
-	directory   BitString
-	data        BitString // data of succinct trie
+	directory   BitVector
+	data        BitVector // data of succinct trie
 	l1Size      uint
 	l2Size      uint
 	l1Bits      uint
 	l2Bits      uint
 	sectionBits uint
 	numBits     uint
+
+	// optional samples for select(), see selectdirectory.go
+	selectSampleRate uint
+	selectOnes       uint
+	selectStart      [2]uint
 }
 
 /**
@@ -39,14 +44,59 @@ type RankDirectory struct {
   summarizes.
 */
 func CreateRankDirectory(data string, numBits, l1Size, l2Size uint) RankDirectory {
-	bits := BitString{}
+	return CreateRankDirectoryWithSelect(data, numBits, l1Size, l2Size, 0)
+}
+
+/**
+  Same as CreateRankDirectory, but the directory also samples the position of
+  every sampleRate-th 0 and 1 bit, so Select does not need a binary search
+  over the whole data. The samples are part of the directory data and are
+  found again by Init. No samples are written if sampleRate is 0.
+*/
+func CreateRankDirectoryWithSelect(data string, numBits, l1Size, l2Size, sampleRate uint) RankDirectory {
+	bits := &BitString{}
+	bits.Init(data)
+	bw := writeRankDirectory(bits, numBits, l1Size, l2Size)
+	writeSelectSamples(bw, bits, numBits, sampleRate)
+	directory := &BitString{}
+	directory.Init(bw.GetData())
+
+	rd := RankDirectory{}
+	rd.init(directory, bits, numBits, l1Size, l2Size)
+	return rd
+}
+
+/**
+  Same as CreateRankDirectory, but for data stored in 64-bit words. The
+  directory is stored in 64-bit words as well.
+*/
+func CreateBinaryRankDirectory(data []uint64, numBits, l1Size, l2Size uint) RankDirectory {
+	return CreateBinaryRankDirectoryWithSelect(data, numBits, l1Size, l2Size, 0)
+}
+
+/**
+  Same as CreateRankDirectoryWithSelect, but for data stored in 64-bit words.
+*/
+func CreateBinaryRankDirectoryWithSelect(data []uint64, numBits, l1Size, l2Size, sampleRate uint) RankDirectory {
+	bits := &BinaryBitString{}
 	bits.Init(data)
+	bw := writeRankDirectory(bits, numBits, l1Size, l2Size)
+	writeSelectSamples(bw, bits, numBits, sampleRate)
+	directory := &BinaryBitString{}
+	directory.Init(bw.GetWords())
+
+	rd := RankDirectory{}
+	rd.init(directory, bits, numBits, l1Size, l2Size)
+	return rd
+}
+
+func writeRankDirectory(bits BitVector, numBits, l1Size, l2Size uint) *BitWriter {
 	var p, i uint = 0, 0
 	var count1, count2 uint = 0, 0
 	l1bits := uint(math.Ceil(math.Log2(float64(numBits))))
 	l2bits := uint(math.Ceil(math.Log2(float64(l1Size))))
 
-	directory := BitWriter{}
+	directory := &BitWriter{}
 
 	for p+l2Size <= numBits {
 		count2 += bits.Count(p, l2Size)
@@ -62,27 +112,60 @@ func CreateRankDirectory(data string, numBits, l1Size, l2Size uint) RankDirector
 		}
 	}
 
-	rd := RankDirectory{}
-	rd.Init(directory.GetData(), data, numBits, l1Size, l2Size)
-	return rd
+	return directory
 }
 
 func (rd *RankDirectory) Init(directoryData, bitData string, numBits, l1Size, l2Size uint) {
-	rd.directory.Init(directoryData)
-	rd.data.Init(bitData)
+	directory := &BitString{}
+	directory.Init(directoryData)
+	data := &BitString{}
+	data.Init(bitData)
+	rd.init(directory, data, numBits, l1Size, l2Size)
+}
+
+/**
+  Same as Init, but for the directory and data stored in 64-bit words.
+*/
+func (rd *RankDirectory) InitBinary(directoryData, bitData []uint64, numBits, l1Size, l2Size uint) {
+	directory := &BinaryBitString{}
+	directory.Init(directoryData)
+	data := &BinaryBitString{}
+	data.Init(bitData)
+	rd.init(directory, data, numBits, l1Size, l2Size)
+}
+
+func (rd *RankDirectory) init(directory, data BitVector, numBits, l1Size, l2Size uint) {
+	rd.directory = directory
+	rd.data = data
 	rd.l1Size = l1Size
 	rd.l2Size = l2Size
 	rd.l1Bits = uint(math.Ceil(math.Log2(float64(numBits))))
 	rd.l2Bits = uint(math.Ceil(math.Log2(float64(l1Size))))
 	rd.sectionBits = (l1Size/l2Size-1)*rd.l2Bits + rd.l1Bits
 	rd.numBits = numBits
+	rd.initSelectSamples()
 }
 
 /**
-  Returns the string representation of the directory.
+  Returns the string representation of the directory. It is empty if the
+  directory is stored in 64-bit words.
 */
 func (rd *RankDirectory) GetData() string {
-	return rd.directory.GetData()
+	if bs, ok := rd.directory.(*BitString); ok {
+		return bs.GetData()
+	}
+	return ""
+}
+
+/**
+  Returns the directory packed into 64-bit words. It is nil if the directory
+  is stored in BASE-64.
+*/
+func (rd *RankDirectory) GetWords() []uint64 {
+	if bs, ok := rd.directory.(*BinaryBitString); ok {
+		return bs.GetWords()
+	}
+	return nil
 }
 
 /**
@@ -120,8 +203,15 @@ func (rd *RankDirectory) Rank(which, x uint) uint {
   parameter.
 */
 func (rd *RankDirectory) Select(which, y uint) uint {
-	high := int(rd.numBits)
-	low := -1
+	if rd.selectSampleRate != 0 && y > 0 {
+		return rd.sampledSelect(which, y)
+	}
+	return rd.searchSelect(which, y, -1, int(rd.numBits))
+}
+
+// searchSelect is Select by binary search over Rank, looking only at the
+// positions between low and high, exclusive.
+func (rd *RankDirectory) searchSelect(which, y uint, low, high int) uint {
 	val := -1
 
 	for high-low > 1 {
@@ -0,0 +1,143 @@
+package bits
+
+/**
+ * Regular expression queries. The regular expression is compiled to the
+ * program of the regexp/syntax package, which is run as an NFA while the trie
+ * is traversed depth-first: every node holds the set of instructions waiting
+ * for the next letter, and the children are only visited with the letters
+ * some instruction accepts. As with regexp.MatchString, the expression
+ * matches a word if it matches any part of it, use ^ and $ to anchor it.
+ */
+
+import (
+	"regexp/syntax"
+	"unicode/utf8"
+)
+
+/**
+  Returns at most limit words of the trie matched by the regular expression,
+  in the order of the alphabet. An error is returned if the expression cannot
+  be compiled. The expression is not normalized: if the trie has
+  normalizers, its letters must already be in the normal form of the words.
+*/
+func (f *FrozenTrie) MatchRegexp(re *syntax.Regexp, limit int) ([]string, error) {
+	var result []string
+
+	prog, err := syntax.Compile(re.Simplify())
+	if err != nil {
+		return result, err
+	}
+	if limit <= 0 {
+		return result, nil
+	}
+
+	rm := regexpMatch{
+		trie:     f,
+		prog:     prog,
+		limit:    limit,
+		anchored: prog.StartCond()&syntax.EmptyBeginText != 0,
+		seen:     make([]bool, len(prog.Inst)),
+	}
+	rm.visit(0, "", []uint32{uint32(prog.Start)}, -1, false)
+	return append(result, rm.result...), nil
+}
+
+type regexpMatch struct {
+	trie     *FrozenTrie
+	prog     *syntax.Prog
+	limit    int
+	anchored bool
+	seen     []bool
+	result   []string
+}
+
+// closure follows the instructions which do not read a letter, in the
+// context between the letters before and after. It returns the instructions
+// waiting for a letter, and true if the expression matched.
+func (rm *regexpMatch) closure(pcs []uint32, before, after rune) ([]uint32, bool) {
+	for i := range rm.seen {
+		rm.seen[i] = false
+	}
+	context := syntax.EmptyOpContext(before, after)
+
+	var result []uint32
+	matched := false
+	stack := append([]uint32{}, pcs...)
+	for len(stack) > 0 {
+		pc := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if rm.seen[pc] {
+			continue
+		}
+		rm.seen[pc] = true
+
+		inst := &rm.prog.Inst[pc]
+		switch inst.Op {
+		case syntax.InstAlt, syntax.InstAltMatch:
+			stack = append(stack, inst.Arg, inst.Out)
+		case syntax.InstCapture, syntax.InstNop:
+			stack = append(stack, inst.Out)
+		case syntax.InstEmptyWidth:
+			if syntax.EmptyOp(inst.Arg)&^context == 0 {
+				stack = append(stack, inst.Out)
+			}
+		case syntax.InstMatch:
+			matched = true
+		case syntax.InstRune, syntax.InstRune1, syntax.InstRuneAny, syntax.InstRuneAnyNotNL:
+			result = append(result, pc)
+		}
+	}
+	return result, matched
+}
+
+// visit traverses the subtrie of the node. pcs are the instructions to run
+// before the next letter, last is the last letter of prefix, or -1 at the
+// root, and matched is true if the expression matched a part of prefix. It
+// returns false when enough words are found.
+func (rm *regexpMatch) visit(index uint, prefix string, pcs []uint32, last rune, matched bool) bool {
+	if rm.trie.isFinal(index) {
+		ok := matched
+		if !ok {
+			_, ok = rm.closure(pcs, last, -1)
+		}
+		if ok {
+			rm.result = append(rm.result, prefix)
+			if len(rm.result) == rm.limit {
+				return false
+			}
+		}
+	}
+
+	children := rm.trie.getSortedChildren(index)
+	var i uint
+	for i = 0; i < children.childCount; i++ {
+		child := children.get(i)
+		letter := rm.trie.getLetter(child)
+		r, _ := utf8.DecodeRuneInString(letter)
+
+		childMatched := matched
+		var next []uint32
+		if !matched {
+			waiting, ok := rm.closure(pcs, last, r)
+			childMatched = ok
+			for _, pc := range waiting {
+				inst := &rm.prog.Inst[pc]
+				if inst.MatchRune(r) {
+					next = append(next, inst.Out)
+				}
+			}
+			// an unanchored expression may start at any letter
+			if !rm.anchored {
+				next = append(next, uint32(rm.prog.Start))
+			}
+			if !childMatched && len(next) == 0 {
+				continue
+			}
+		}
+
+		if !rm.visit(child, prefix+letter, next, r, childMatched) {
+			return false
+		}
+	}
+	return true
+}
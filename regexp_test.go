@@ -0,0 +1,51 @@
+package bits
+
+import (
+	"reflect"
+	"regexp/syntax"
+	"testing"
+)
+
+func TestMatchRegexp(t *testing.T) {
+	te := Trie{}
+	te.InitWithAlphabet(CreateAlphabet("abcdeghijklmnoprstuvyāīūṁṃŋṇṅñṭḍḷ…'’° -"))
+	te.Insert("gacchati")
+	te.Insert("bhavati")
+	te.Insert("hoti")
+	te.Insert("atiriva")
+	te.Insert("bhava")
+	te.Insert("dhammaṃ")
+	teData := te.Encode()
+	rd := CreateRankDirectory(teData, te.GetNodeCount()*2+1, L1, L2)
+	ft := FrozenTrie{}
+	ft.InitWithAlphabet(teData, rd.GetData(), te.GetNodeCount(), te.GetAlphabet())
+
+	cases := []struct {
+		expr     string
+		limit    int
+		expected []string
+	}{
+		{"ati$", 10, []string{"bhavati", "gacchati"}},
+		{"ati", 10, []string{"atiriva", "bhavati", "gacchati"}},
+		{"^bhav", 10, []string{"bhava", "bhavati"}},
+		{"^bhava$", 10, []string{"bhava"}},
+		{"^(ho|gaccha)ti$", 10, []string{"gacchati", "hoti"}},
+		{"[ṃm]$", 10, []string{"dhammaṃ"}},
+		{`\bhoti\b`, 10, []string{"hoti"}},
+		{"ti", 2, []string{"atiriva", "bhavati"}},
+		{"^x", 10, nil},
+	}
+	for _, c := range cases {
+		re, err := syntax.Parse(c.expr, syntax.Perl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		words, err := ft.MatchRegexp(re, c.limit)
+		if err != nil {
+			t.Error(c.expr, err)
+		}
+		if !reflect.DeepEqual(words, c.expected) {
+			t.Error("MatchRegexp", c.expr, words)
+		}
+	}
+}
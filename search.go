@@ -1,26 +1,22 @@
 package bits
 
 /**
- * Given a word, returns array of words, prefix of which is word
+ * Given a word, returns array of words, prefix of which is word. The word is
+ * normalized first if the trie has normalizers.
  */
 func (f *FrozenTrie) GetSuggestedWords(word string, limit int) []string {
 	var result []string
 
+	word = f.normalizeWord(word)
+
 	node := f.GetRoot()
 
 	// find the node corresponding to the last char of input
 	for _, runeValue := range word {
-		var child FrozenTrieNode
-		var j uint = 0
-		for ; j < node.GetChildCount(); j++ {
-			child = node.GetChild(j)
-			if child.letter == string(runeValue) {
-				break
-			}
-		}
+		child, ok := f.findChild(node, string(runeValue))
 
 		// not found, return.
-		if j == node.GetChildCount() {
+		if !ok {
 			return result
 		}
 
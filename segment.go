@@ -0,0 +1,132 @@
+package bits
+
+/**
+ * Dictionary based word segmentation, for text written without spaces. For
+ * every position of the text, the words starting there are found by a single
+ * prefix walk of the trie (see prefix.go). Dynamic programming from the end
+ * of the text then finds the positions from which the rest of the text can be
+ * split into words, so that only the splits which succeed are enumerated.
+ */
+
+// segmentEnd is the end of a word in the text, and the index of the final
+// node of the word.
+type segmentEnd struct {
+	end   int
+	index uint
+}
+
+// segmentEnds returns, for every byte position of the text, the ends of the
+// words of the trie starting there, the longest first. Positions inside a
+// UTF-8 sequence have no words.
+func (f *FrozenTrie) segmentEnds(text string) [][]segmentEnd {
+	ends := make([][]segmentEnd, len(text)+1)
+	for i := range text {
+		f.walkPrefixes(text[i:], func(n int, index uint) bool {
+			// the empty word does not split anything
+			if n > 0 {
+				ends[i] = append([]segmentEnd{{i + n, index}}, ends[i]...)
+			}
+			return true
+		})
+	}
+	return ends
+}
+
+/**
+  Returns at most limit ways to split text into words of the trie. Splits
+  using longer words first are returned first.
+*/
+func (f *FrozenTrie) Segment(text string, limit int) [][]string {
+	var result [][]string
+	if limit <= 0 {
+		return result
+	}
+
+	ends := f.segmentEnds(text)
+
+	// splittable[i] is true if text[i:] can be split into words
+	splittable := make([]bool, len(text)+1)
+	splittable[len(text)] = true
+	for i := len(text) - 1; i >= 0; i-- {
+		for _, e := range ends[i] {
+			if splittable[e.end] {
+				splittable[i] = true
+				break
+			}
+		}
+	}
+	if len(text) == 0 || !splittable[0] {
+		return result
+	}
+
+	var words []string
+	var split func(i int) bool
+	split = func(i int) bool {
+		if i == len(text) {
+			result = append(result, append([]string{}, words...))
+			return len(result) < limit
+		}
+		for _, e := range ends[i] {
+			if !splittable[e.end] {
+				continue
+			}
+			words = append(words, text[i:e.end])
+			more := split(e.end)
+			words = words[:len(words)-1]
+			if !more {
+				return false
+			}
+		}
+		return true
+	}
+	split(0)
+
+	return result
+}
+
+/**
+  Returns the best way to split text into words of the trie, and true if
+  there is one. The best split has the largest sum of word weights (see
+  Trie.InsertWithWeight), then the fewest words.
+*/
+func (f *FrozenTrie) SegmentBest(text string) ([]string, bool) {
+	if len(text) == 0 {
+		return nil, false
+	}
+	ends := f.segmentEnds(text)
+
+	type best struct {
+		ok     bool
+		weight uint64
+		count  int
+		next   int
+	}
+	bests := make([]best, len(text)+1)
+	bests[len(text)] = best{ok: true}
+	for i := len(text) - 1; i >= 0; i-- {
+		for _, e := range ends[i] {
+			if !bests[e.end].ok {
+				continue
+			}
+			b := best{
+				ok:     true,
+				weight: bests[e.end].weight + f.weights.get(f.wordID(e.index)),
+				count:  bests[e.end].count + 1,
+				next:   e.end,
+			}
+			if !bests[i].ok || b.weight > bests[i].weight ||
+				(b.weight == bests[i].weight && b.count < bests[i].count) {
+				bests[i] = b
+			}
+		}
+	}
+	if !bests[0].ok {
+		return nil, false
+	}
+
+	var words []string
+	for i := 0; i < len(text); i = bests[i].next {
+		words = append(words, text[i:bests[i].next])
+	}
+	return words, true
+}
@@ -0,0 +1,49 @@
+package bits
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestSegment(t *testing.T) {
+	te := Trie{}
+	te.Init()
+	te.InsertWithWeight("a", 1)
+	te.InsertWithWeight("an", 1)
+	te.InsertWithWeight("and", 5)
+	te.InsertWithWeight("sand", 5)
+	te.InsertWithWeight("d", 1)
+	te.InsertWithWeight("nd", 1)
+	var buf bytes.Buffer
+	if err := te.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	ft, err := Load(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := [][]string{
+		{"and", "a", "sand"},
+		{"an", "d", "a", "sand"},
+		{"a", "nd", "a", "sand"},
+	}
+	if !reflect.DeepEqual(ft.Segment("andasand", 10), expected) {
+		t.Error(`Segment("andasand", 10)`, ft.Segment("andasand", 10))
+	}
+	if !reflect.DeepEqual(ft.Segment("andasand", 2), expected[:2]) {
+		t.Error(`Segment("andasand", 2)`, ft.Segment("andasand", 2))
+	}
+	if len(ft.Segment("andx", 10)) != 0 {
+		t.Error(`Segment("andx", 10)`)
+	}
+
+	words, ok := ft.SegmentBest("andasand")
+	if !ok || !reflect.DeepEqual(words, []string{"and", "a", "sand"}) {
+		t.Error(`SegmentBest("andasand")`, words, ok)
+	}
+	if _, ok := ft.SegmentBest("x"); ok {
+		t.Error(`SegmentBest("x")`)
+	}
+}
@@ -0,0 +1,141 @@
+package bits
+
+/**
+ * Samples for the select() function of the RankDirectory.
+ *
+ * The samples are written in the directory after the rank entries:
+ *
+ *   sample rate k (32 bits) | positions of the 1st, (k+1)th, (2k+1)th ...
+ *   0 bit | positions of the 1st, (k+1)th, (2k+1)th ... 1 bit
+ *
+ * Every position takes as many bits as a L1 entry. Select starts from the
+ * sample before the wanted bit and scans the data from there, so it takes
+ * near constant time instead of a binary search over all bits.
+ */
+
+import "math"
+
+const selectSampleRateBits = 32
+
+func writeSelectSamples(bw *BitWriter, bits BitVector, numBits, sampleRate uint) {
+	if sampleRate == 0 {
+		return
+	}
+
+	posBits := uint(math.Ceil(math.Log2(float64(numBits))))
+	bw.Write(sampleRate, selectSampleRateBits)
+
+	var which uint
+	for which = 0; which <= 1; which++ {
+		var count, p uint = 0, 0
+		for p = 0; p < numBits; p++ {
+			if bits.Get(p, 1) != which {
+				continue
+			}
+			if count%sampleRate == 0 {
+				bw.Write(p, posBits)
+			}
+			count++
+		}
+	}
+}
+
+// rankBits returns the number of bits used by the rank entries of the
+// directory, which are followed by the select samples.
+func (rd *RankDirectory) rankBits() uint {
+	l2Entries := rd.numBits / rd.l2Size
+	l1Entries := rd.numBits / rd.l1Size
+	return l1Entries*rd.l1Bits + (l2Entries-l1Entries)*rd.l2Bits
+}
+
+// initSelectSamples looks for select samples after the rank entries. If
+// there are none, Select falls back to binary search.
+func (rd *RankDirectory) initSelectSamples() {
+	rd.selectSampleRate = 0
+
+	start := rd.rankBits()
+	if rd.directory.Length() < start+selectSampleRateBits {
+		return
+	}
+	sampleRate := rd.directory.Get(start, selectSampleRateBits)
+	if sampleRate == 0 {
+		return
+	}
+
+	ones := rd.Rank(1, rd.numBits-1)
+	zeros := rd.numBits - ones
+	zeroStart := start + selectSampleRateBits
+	oneStart := zeroStart + (zeros+sampleRate-1)/sampleRate*rd.l1Bits
+	if rd.directory.Length() < oneStart+(ones+sampleRate-1)/sampleRate*rd.l1Bits {
+		return
+	}
+
+	rd.selectSampleRate = sampleRate
+	rd.selectOnes = ones
+	rd.selectStart = [2]uint{zeroStart, oneStart}
+}
+
+/**
+  Returns the number of positions between the sampled positions of select(),
+  or 0 if the directory has no samples.
+*/
+func (rd *RankDirectory) GetSelectSampleRate() uint {
+	return rd.selectSampleRate
+}
+
+// selectSample returns the position of the (i*sampleRate+1)th 0 or 1 bit.
+func (rd *RankDirectory) selectSample(which, i uint) uint {
+	return rd.directory.Get(rd.selectStart[which]+i*rd.l1Bits, rd.l1Bits)
+}
+
+func (rd *RankDirectory) sampledSelect(which, y uint) uint {
+	total := rd.selectOnes
+	if which == 0 {
+		total = rd.numBits - rd.selectOnes
+	}
+	if y > total {
+		return rd.searchSelect(which, y, -1, int(rd.numBits))
+	}
+
+	i := (y - 1) / rd.selectSampleRate
+	p := rd.selectSample(which, i)
+	end := rd.numBits
+	if (i+1)*rd.selectSampleRate < total {
+		end = rd.selectSample(which, i+1)
+	}
+
+	// the bits between two samples may be too many to scan, for example a
+	// long run of 1 bits when looking for 0 bits.
+	if end-p > 4*rd.l2Size {
+		return rd.searchSelect(which, y, int(p)-1, int(end))
+	}
+
+	// the number of bits to find, including the one at p
+	r := y - i*rd.selectSampleRate
+	for p < end {
+		n := end - p
+		if n > 8 {
+			n = 8
+		}
+		count := BitsInByte[rd.data.Get(p, n)]
+		if which == 0 {
+			count = n - count
+		}
+		if count < r {
+			r -= count
+			p += n
+			continue
+		}
+
+		for ; ; p++ {
+			if rd.data.Get(p, 1) == which {
+				r--
+				if r == 0 {
+					return p
+				}
+			}
+		}
+	}
+
+	return rd.searchSelect(which, y, -1, int(rd.numBits))
+}
@@ -0,0 +1,100 @@
+package bits
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+)
+
+func TestSelectSamples(t *testing.T) {
+	bw := BitWriter{}
+	r := rand.New(rand.NewSource(2))
+	for i := 0; i < 3000; i++ {
+		// long runs of 1 bits, like the children of a node with many
+		// children, and scattered 0 bits.
+		if i%500 < 200 {
+			bw.Write(1, 1)
+		} else {
+			bw.Write(uint(r.Intn(2)), 1)
+		}
+	}
+	var numBits uint = 2990
+
+	rd := CreateRankDirectory(bw.GetData(), numBits, L1, L2)
+	srd := CreateRankDirectoryWithSelect(bw.GetData(), numBits, L1, L2, 16)
+	brd := CreateBinaryRankDirectoryWithSelect(bw.GetWords(), numBits, L1, L2, 16)
+	if rd.GetSelectSampleRate() != 0 {
+		t.Error("rd.GetSelectSampleRate() != 0")
+	}
+	if srd.GetSelectSampleRate() != 16 || brd.GetSelectSampleRate() != 16 {
+		t.Error("select samples not found")
+	}
+
+	loaded := RankDirectory{}
+	loaded.Init(srd.GetData(), bw.GetData(), numBits, L1, L2)
+	if loaded.GetSelectSampleRate() != 16 {
+		t.Error("select samples not found by Init")
+	}
+
+	var which, y uint
+	for which = 0; which <= 1; which++ {
+		for y = 0; y <= rd.Rank(which, numBits-1)+1; y++ {
+			expected := rd.Select(which, y)
+			if srd.Select(which, y) != expected {
+				t.Error("Select", which, y, expected, srd.Select(which, y))
+			}
+			if brd.Select(which, y) != expected {
+				t.Error("binary Select", which, y, expected, brd.Select(which, y))
+			}
+			if loaded.Select(which, y) != expected {
+				t.Error("loaded Select", which, y, expected, loaded.Select(which, y))
+			}
+		}
+	}
+}
+
+func TestSelectSamplesWholeData(t *testing.T) {
+	// the counts end at the end of the data, a multiple of 8 and 6 bits
+	bw := BitWriter{}
+	for i := 0; i < 48; i++ {
+		bw.Write(uint(i%3)&1, 1)
+	}
+	rd := CreateRankDirectory(bw.GetData(), 48, L1, L2)
+	srd := CreateRankDirectoryWithSelect(bw.GetData(), 48, L1, L2, 64)
+	if srd.GetSelectSampleRate() != 64 {
+		t.Error("select samples not found")
+	}
+	var which, y uint
+	for which = 0; which <= 1; which++ {
+		for y = 0; y <= rd.Rank(which, 47)+1; y++ {
+			if srd.Select(which, y) != rd.Select(which, y) {
+				t.Error("Select", which, y, rd.Select(which, y), srd.Select(which, y))
+			}
+		}
+	}
+}
+
+func TestSaveWithSelectSamples(t *testing.T) {
+	te := Trie{}
+	te.Init()
+	insertNotInAlphabeticalOrder(&te)
+	te.SetSelectSampleRate(4)
+
+	var buf bytes.Buffer
+	if err := te.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	ft, err := Load(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ft.directory.GetSelectSampleRate() != 4 {
+		t.Error("select samples not loaded")
+	}
+	if ft.Lookup("jello") != true {
+		t.Error("jello")
+	}
+	if ft.Lookup("jell") != false {
+		t.Error("jell")
+	}
+}
@@ -0,0 +1,284 @@
+package bits
+
+/**
+ * Building the encoded trie from sorted words without keeping the tree in
+ * memory. When the words come in the order of the alphabet, the nodes of
+ * every level are created in level order, and a node is complete as soon as
+ * a word leaves its path: its "final" indicator is known when it is created,
+ * and no child is added to it later. So, as in Trie.Insert, only the path of
+ * the previous word is kept in memory, and every node leaving it is appended
+ * to a temporary file for its level. Finish then reads the levels one after
+ * the other, twice: once for the unary encoding of the children, and once
+ * for the data of the nodes.
+ */
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"unicode/utf8"
+)
+
+// ErrBuilderClosed is returned by StreamBuilder once it is finished or
+// closed.
+var ErrBuilderClosed = errors.New("bits: stream builder closed")
+
+/**
+  Builds the encoded trie, with the same bits as Trie.EncodeBinary, from words
+  inserted in the order of the alphabet (see Alphabet.Compare). The memory
+  used is proportional to the length of the longest word, plus the encoded
+  trie itself.
+*/
+type StreamBuilder struct {
+	alphabet     *Alphabet
+	tempDir      string
+	previousWord string
+	hasWords     bool
+	// the nodes on the path of the previous word, the root first
+	cache     []streamNode
+	nodeCount uint
+	levels    []*streamLevel
+	err       error
+}
+
+type streamNode struct {
+	code     uint
+	final    bool
+	children uint
+}
+
+// streamLevel is the temporary file of the nodes of one level.
+type streamLevel struct {
+	file *os.File
+	w    *bufio.Writer
+}
+
+/**
+  Initialize the builder with the default alphabet.
+*/
+func (s *StreamBuilder) Init() {
+	s.InitWithAlphabet(defaultAlphabet)
+}
+
+/**
+  Initialize the builder with the given alphabet. The alphabet must have a
+  space, ErrNoSpace is returned by Insert and Finish otherwise.
+*/
+func (s *StreamBuilder) InitWithAlphabet(alphabet *Alphabet) {
+	s.alphabet = alphabet
+	s.previousWord = ""
+	s.hasWords = false
+	code, ok := alphabet.encode(" ")
+	s.cache = []streamNode{{code: code}}
+	s.nodeCount = 1
+	s.levels = nil
+	s.err = nil
+	if !ok {
+		s.err = ErrNoSpace
+	}
+}
+
+/**
+  Set the directory of the temporary files. The default directory for
+  temporary files is used if dir is "".
+*/
+func (s *StreamBuilder) SetTempDir(dir string) {
+	s.tempDir = dir
+}
+
+/**
+  Returns the number of nodes in the trie
+*/
+func (s *StreamBuilder) GetNodeCount() uint {
+	return s.nodeCount
+}
+
+/**
+  Inserts a word into the trie. The words must be inserted in the order of
+  the alphabet, ErrWordOrder is returned otherwise. Inserting the previous
+  word again does nothing. An *IllegalCharacterError is returned if the word
+  has a letter not in the alphabet. Errors writing the temporary files are
+  returned by this and all the later calls.
+*/
+func (s *StreamBuilder) Insert(word string) error {
+	if s.err != nil {
+		return s.err
+	}
+	codes := make([]uint, 0, len(word))
+	for _, runeValue := range word {
+		code, ok := s.alphabet.encode(string(runeValue))
+		if !ok {
+			return &IllegalCharacterError{Word: word, Rune: runeValue}
+		}
+		codes = append(codes, code)
+	}
+	if s.hasWords {
+		switch s.alphabet.Compare(word, s.previousWord) {
+		case 0:
+			return nil
+		case -1:
+			return ErrWordOrder
+		}
+	}
+
+	commonRuneCount := 0
+	for i, j := 0, 0; i < len(word) && j < len(s.previousWord); commonRuneCount++ {
+		runeValue1, width1 := utf8.DecodeRuneInString(word[i:])
+		runeValue2, width2 := utf8.DecodeRuneInString(s.previousWord[j:])
+		if runeValue1 != runeValue2 {
+			break
+		}
+		i += width1
+		j += width2
+	}
+
+	if err := s.closeNodes(commonRuneCount + 1); err != nil {
+		return err
+	}
+	for _, code := range codes[commonRuneCount:] {
+		s.cache[len(s.cache)-1].children++
+		s.cache = append(s.cache, streamNode{code: code})
+		s.nodeCount++
+	}
+	s.cache[len(s.cache)-1].final = true
+
+	s.previousWord = word
+	s.hasWords = true
+	return nil
+}
+
+// closeNodes writes the nodes of the path deeper than depth to their levels.
+func (s *StreamBuilder) closeNodes(depth int) error {
+	for len(s.cache) > depth {
+		level := len(s.cache) - 1
+		node := s.cache[level]
+		s.cache = s.cache[:level]
+
+		if err := s.writeNode(level, node); err != nil {
+			s.err = err
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *StreamBuilder) writeNode(level int, node streamNode) error {
+	for len(s.levels) <= level {
+		file, err := ioutil.TempFile(s.tempDir, "succinct-trie-level-")
+		if err != nil {
+			return err
+		}
+		s.levels = append(s.levels, &streamLevel{file: file, w: bufio.NewWriter(file)})
+	}
+
+	value := uint64(node.code) << 1
+	if node.final {
+		value |= 1
+	}
+	record := appendUvarint(nil, uint64(node.children))
+	record = appendUvarint(record, value)
+	_, err := s.levels[level].w.Write(record)
+	return err
+}
+
+/**
+  Encode the trie. Returns the encoded data and its rank directory, both
+  packed into 64-bit words, to be passed to FrozenTrie.InitBinary. The
+  children are in the order of the alphabet, see
+  FrozenTrie.SetSortedChildren. The temporary files are removed, and no words
+  can be inserted afterwards.
+*/
+func (s *StreamBuilder) Finish() ([]uint64, []uint64, error) {
+	defer s.Close()
+	if s.err != nil {
+		return nil, nil, s.err
+	}
+	if err := s.closeNodes(0); err != nil {
+		return nil, nil, err
+	}
+	for _, level := range s.levels {
+		if err := level.w.Flush(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Write the unary encoding of the tree in level order.
+	bits := &BitWriter{}
+	bits.Write(0x02, 2)
+	err := s.readLevels(func(children, value uint64) {
+		for ; children > 32; children -= 32 {
+			bits.Write(0xffffffff, 32)
+		}
+		bits.Write(uint(1<<children-1), uint(children))
+		bits.Write(0, 1)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Write the data for each node, as Trie.Encode.
+	dataBits := s.alphabet.GetDataBits()
+	err = s.readLevels(func(children, value uint64) {
+		data := uint(value >> 1)
+		if value&1 == 1 {
+			data |= 1 << (dataBits - 1)
+		}
+		bits.Write(data, dataBits)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rd := CreateBinaryRankDirectory(bits.words, s.nodeCount*2+1, L1, L2)
+	return bits.words, rd.GetWords(), nil
+}
+
+// readLevels calls fn with the number of children and the value of every
+// node, in level order.
+func (s *StreamBuilder) readLevels(fn func(children, value uint64)) error {
+	for _, level := range s.levels {
+		if _, err := level.file.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		r := bufio.NewReader(level.file)
+		for {
+			children, err := binary.ReadUvarint(r)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			value, err := binary.ReadUvarint(r)
+			if err != nil {
+				return err
+			}
+			fn(children, value)
+		}
+	}
+	return nil
+}
+
+/**
+  Remove the temporary files. Finish calls it, it is only needed if Finish
+  is not called.
+*/
+func (s *StreamBuilder) Close() error {
+	var result error
+	for _, level := range s.levels {
+		if err := level.file.Close(); err != nil && result == nil {
+			result = err
+		}
+		if err := os.Remove(level.file.Name()); err != nil && result == nil {
+			result = err
+		}
+	}
+	s.levels = nil
+	if s.err == nil {
+		s.err = ErrBuilderClosed
+	}
+	return result
+}
@@ -0,0 +1,97 @@
+package bits
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestStreamBuilder(t *testing.T) {
+	alphabet := CreateAlphabet("abcdeghijklmnoprstuvyāīūṁṃŋṇṅñṭḍḷ…'’° -")
+	words := []string{"", "ariya", "ariyasacca", "buddha", "buddhaṃ", "dhamma",
+		"dhammacakkappavattana", "dhammaṃ", "sacca", "saccavācā"}
+
+	dir, err := ioutil.TempDir("", "streambuilder")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	sb := StreamBuilder{}
+	sb.InitWithAlphabet(alphabet)
+	sb.SetTempDir(dir)
+	te := Trie{}
+	te.InitWithAlphabet(alphabet)
+	for _, word := range words {
+		if err := sb.Insert(word); err != nil {
+			t.Fatal(err)
+		}
+		te.Insert(word)
+	}
+	data, directory, err := sb.Finish()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if sb.GetNodeCount() != te.GetNodeCount() {
+		t.Error("GetNodeCount", sb.GetNodeCount(), te.GetNodeCount())
+	}
+	if !reflect.DeepEqual(data, te.EncodeBinary()) {
+		t.Error("Finish data")
+	}
+	rd := CreateBinaryRankDirectory(te.EncodeBinary(), te.GetNodeCount()*2+1, L1, L2)
+	if !reflect.DeepEqual(directory, rd.GetWords()) {
+		t.Error("Finish directory")
+	}
+
+	ft := FrozenTrie{}
+	ft.InitBinary(data, directory, sb.GetNodeCount(), alphabet)
+	ft.SetSortedChildren(true)
+	for _, word := range words {
+		if !ft.Lookup(word) {
+			t.Error("Lookup", word)
+		}
+	}
+
+	if files, _ := ioutil.ReadDir(dir); len(files) != 0 {
+		t.Error("temporary files not removed", len(files))
+	}
+	if err := sb.Insert("vihara"); err != ErrBuilderClosed {
+		t.Error("Insert after Finish", err)
+	}
+}
+
+func TestStreamBuilderInsertOrder(t *testing.T) {
+	sb := StreamBuilder{}
+	sb.Init()
+	defer sb.Close()
+
+	sb.Insert("b")
+	if err := sb.Insert("b"); err != nil {
+		t.Error("Insert the same word", err)
+	}
+	if err := sb.Insert("ab"); err != ErrWordOrder {
+		t.Error("Insert before", err)
+	}
+	if _, ok := sb.Insert("B").(*IllegalCharacterError); !ok {
+		t.Error("Insert illegal character")
+	}
+	if err := sb.Insert("ba"); err != nil {
+		t.Error("Insert after", err)
+	}
+	if sb.GetNodeCount() != 3 {
+		t.Error("GetNodeCount", sb.GetNodeCount())
+	}
+}
+
+func TestStreamBuilderNoSpace(t *testing.T) {
+	sb := StreamBuilder{}
+	sb.InitWithAlphabet(CreateAlphabet("ab"))
+	if err := sb.Insert("ab"); err != ErrNoSpace {
+		t.Error("Insert", err)
+	}
+	if _, _, err := sb.Finish(); err != ErrNoSpace {
+		t.Error("Finish", err)
+	}
+}
@@ -0,0 +1,119 @@
+package bits
+
+/**
+ * Suffix search. A second trie is built from the reversed words, with the
+ * same alphabet, and the value of every word of the reversed trie is the ID
+ * of the forward word (see wordid.go). The words ending with a suffix are
+ * the words of the reversed trie starting with the reversed suffix, which are
+ * mapped back to the forward spellings by their IDs.
+ */
+
+import "sort"
+
+/**
+  Set whether the reversed trie used by FrozenTrie.GetWordsWithSuffix is
+  written when the trie is saved.
+*/
+func (t *Trie) SetBuildSuffixIndex(build bool) {
+	t.buildSuffixIndex = build
+}
+
+// buildReversed returns the trie of the reversed words, with the ID of the
+// forward word as the value of every word.
+func (t *Trie) buildReversed() *Trie {
+	type reversedWord struct {
+		word string
+		id   uint64
+	}
+	var words []reversedWord
+
+	// the reversed letters on the path to every node
+	reversed := map[*TrieNode]string{t.root: ""}
+	var id uint64 = 0
+	t.Apply(func(node *TrieNode) {
+		for _, child := range node.children {
+			reversed[child] = child.letter + reversed[node]
+		}
+		if node.final {
+			words = append(words, reversedWord{reversed[node], id})
+			id++
+		}
+		delete(reversed, node)
+	})
+
+	// Insert is fastest in sorted order
+	sort.Slice(words, func(i, j int) bool {
+		return words[i].word < words[j].word
+	})
+
+	rt := &Trie{}
+	rt.InitWithAlphabet(t.GetAlphabet())
+	rt.SetStorage(t.storage)
+	rt.SetSelectSampleRate(t.selectSampleRate)
+	for _, w := range words {
+		// the letters are in the alphabet, they are the letters of t
+		rt.InsertWithValue(w.word, w.id)
+	}
+	return rt
+}
+
+/**
+  Returns at most limit words of the trie ending with suffix, in the order
+  of the alphabet of their reversed spelling, so words sharing a longer
+  ending are next to each other. No words are returned if the trie was not
+  saved with a suffix index, see Trie.SetBuildSuffixIndex. The suffix is
+  normalized first if the trie has normalizers.
+*/
+func (f *FrozenTrie) GetWordsWithSuffix(suffix string, limit int) []string {
+	var result []string
+	if f.suffixes == nil || limit <= 0 {
+		return result
+	}
+
+	letters := []rune(f.normalizeWord(suffix))
+	for i, j := 0, len(letters)-1; i < j; i, j = i+1, j-1 {
+		letters[i], letters[j] = letters[j], letters[i]
+	}
+	node, ok := f.suffixes.findNode(string(letters))
+	if !ok {
+		return result
+	}
+
+	// depth-first, as Iterator
+	stack := []uint{node.index}
+	for len(stack) > 0 {
+		index := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if f.suffixes.isFinal(index) {
+			id := f.suffixes.values.get(f.suffixes.wordID(index))
+			result = append(result, f.spell(f.wordNode(uint(id))))
+			if len(result) == limit {
+				return result
+			}
+		}
+
+		children := f.suffixes.getSortedChildren(index)
+		for i := children.childCount; i > 0; i-- {
+			stack = append(stack, children.get(i-1))
+		}
+	}
+	return result
+}
+
+// checkSuffixIndex makes sure the reversed trie has a word for every word of
+// the trie, and that its values are word IDs.
+func (f *FrozenTrie) checkSuffixIndex() bool {
+	rt := f.suffixes
+	n := f.GetWordCount()
+	if rt.GetWordCount() != n || !rt.values.fits() {
+		return false
+	}
+	var id uint
+	for id = 0; id < n; id++ {
+		if rt.values.get(id) >= uint64(n) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,59 @@
+package bits
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestGetWordsWithSuffix(t *testing.T) {
+	for _, storage := range []Storage{StorageBase64, StorageBinary} {
+		te := Trie{}
+		te.InitWithAlphabet(CreateAlphabet("abcdeghijklmnoprstuvyāīūṁṃŋṇṅñṭḍḷ…'’° -"))
+		for _, word := range []string{"buddha", "buddhaṃ", "dhamma", "dhammaṃ", "saṅgha", "saṅghaṃ", "sā"} {
+			te.Insert(word)
+		}
+		te.SetStorage(storage)
+		te.SetBuildSuffixIndex(true)
+
+		var buf bytes.Buffer
+		if err := te.Save(&buf); err != nil {
+			t.Fatal(err)
+		}
+		ft, err := Load(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// sorted by the reversed spelling: "ahddub" < "ahgṅas" < "ammahd"
+		if result := ft.GetWordsWithSuffix("a", 10); !reflect.DeepEqual(result, []string{"buddha", "saṅgha", "dhamma"}) {
+			t.Error("GetWordsWithSuffix a", result)
+		}
+		if result := ft.GetWordsWithSuffix("aṃ", 2); !reflect.DeepEqual(result, []string{"buddhaṃ", "saṅghaṃ"}) {
+			t.Error("GetWordsWithSuffix aṃ", result)
+		}
+		if result := ft.GetWordsWithSuffix("dhamma", 10); !reflect.DeepEqual(result, []string{"dhamma"}) {
+			t.Error("GetWordsWithSuffix dhamma", result)
+		}
+		if result := ft.GetWordsWithSuffix("xa", 10); len(result) != 0 {
+			t.Error("GetWordsWithSuffix xa", result)
+		}
+		if n := len(ft.GetWordsWithSuffix("", 100)); n != 7 {
+			t.Error("GetWordsWithSuffix empty suffix", n)
+		}
+	}
+
+	// no suffix index
+	te := Trie{}
+	te.Init()
+	te.Insert("ab")
+	var buf bytes.Buffer
+	te.Save(&buf)
+	ft, err := Load(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result := ft.GetWordsWithSuffix("b", 10); len(result) != 0 {
+		t.Error("GetWordsWithSuffix without index", result)
+	}
+}
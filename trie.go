@@ -2,7 +2,10 @@
 // structure in Go.
 package bits
 
-import "unicode/utf8"
+import (
+	"sort"
+	"unicode/utf8"
+)
 
 // https://blog.golang.org/strings
 // https://golang.org/pkg/unicode/utf8/
@@ -15,6 +18,8 @@ type TrieNode struct {
 	letter   string
 	final    bool
 	children []*TrieNode
+	value    uint64
+	weight   uint64
 }
 
 type Trie struct {
@@ -22,9 +27,41 @@ type Trie struct {
 	root         *TrieNode
 	cache        []*TrieNode
 	nodeCount    uint
+	alphabet     *Alphabet
+	storage      Storage
+
+	selectSampleRate uint
+	hasValues        bool
+	hasWeights       bool
+
+	buildFailureLinks bool
+	buildSuffixIndex  bool
+
+	// the runes of the words, if the alphabet is derived from them, see
+	// derivedalphabet.go
+	runeCounts    map[rune]uint64
+	alphabetOrder AlphabetOrder
+
+	// see normalizer.go
+	normalizers []string
+	normalize   Normalizer
 }
 
+/**
+  Initialize the trie with the default alphabet.
+*/
 func (t *Trie) Init() {
+	t.InitWithAlphabet(defaultAlphabet)
+}
+
+/**
+  Initialize the trie with the given alphabet, which is used to encode the
+  letters of the nodes. The alphabet must have a space, the letter of the
+  root, ErrNoSpace is returned by Insert and Save otherwise.
+*/
+func (t *Trie) InitWithAlphabet(alphabet *Alphabet) {
+	t.alphabet = alphabet
+	t.runeCounts = nil
 	t.previousWord = ""
 	t.root = &TrieNode{
 		letter: " ",
@@ -34,6 +71,53 @@ func (t *Trie) Init() {
 	t.nodeCount = 1
 }
 
+/**
+  Returns the alphabet of the trie. If the trie was initialized with
+  InitWithDerivedAlphabet, it is the alphabet of the words inserted so far.
+*/
+func (t *Trie) GetAlphabet() *Alphabet {
+	if t.alphabet == nil && t.runeCounts != nil {
+		t.alphabet = deriveAlphabet(t.runeCounts, t.alphabetOrder)
+	}
+	return t.alphabet
+}
+
+/**
+  Set how the encoded bits are stored when the trie is saved. The default is
+  StorageBase64.
+*/
+func (t *Trie) SetStorage(storage Storage) {
+	t.storage = storage
+}
+
+/**
+  Returns how the encoded bits are stored when the trie is saved
+*/
+func (t *Trie) GetStorage() Storage {
+	return t.storage
+}
+
+/**
+  Set the sample rate of the select samples written in the rank directory
+  when the trie is saved. 0, the default, writes no samples. See
+  CreateRankDirectoryWithSelect.
+*/
+func (t *Trie) SetSelectSampleRate(sampleRate uint) {
+	t.selectSampleRate = sampleRate
+}
+
+// checkAlphabet returns ErrNoSpace if the alphabet cannot encode the root. A
+// derived alphabet always has a space.
+func (t *Trie) checkAlphabet() error {
+	if t.runeCounts != nil {
+		return nil
+	}
+	if _, ok := t.alphabet.encode(" "); !ok {
+		return ErrNoSpace
+	}
+	return nil
+}
+
 /**
   Returns the number of nodes in the trie
 */
@@ -43,9 +127,26 @@ func (t *Trie) GetNodeCount() uint {
 
 /**
   Inserts a word into the trie. This function is fastest if the words are
-  inserted in alphabetical order.
+  inserted in alphabetical order. An *IllegalCharacterError is returned, and
+  the trie is left unchanged, if the word has a letter not in the alphabet.
+  The word is normalized first if the trie has normalizers.
 */
-func (t *Trie) Insert(word string) {
+func (t *Trie) Insert(word string) error {
+	if err := t.checkAlphabet(); err != nil {
+		return err
+	}
+	if t.normalize != nil {
+		word = t.normalize(word)
+	}
+	if t.runeCounts != nil {
+		t.countRunes(word)
+	} else {
+		for _, runeValue := range word {
+			if _, ok := t.alphabet.encode(string(runeValue)); !ok {
+				return &IllegalCharacterError{Word: word, Rune: runeValue}
+			}
+		}
+	}
 
 	commonPrefixWidth := 0
 	commonRuneCount := 0
@@ -96,10 +197,13 @@ func (t *Trie) Insert(word string) {
 
 	node.final = true
 	t.previousWord = word
+	return nil
 }
 
 /**
-  Apply a function to each node, traversing the trie in level order.
+  Apply a function to each node, traversing the trie in level order. The
+  children of every node are visited in the order of the alphabet, which is
+  the order they are encoded in.
 */
 func (t *Trie) Apply(fn func(*TrieNode)) {
 	var level []*TrieNode
@@ -107,6 +211,7 @@ func (t *Trie) Apply(fn func(*TrieNode)) {
 	for len(level) > 0 {
 		node := level[0]
 		level = level[1:]
+		t.sortChildren(node)
 		for i := 0; i < len(node.children); i++ {
 			level = append(level, node.children[i])
 		}
@@ -114,13 +219,39 @@ func (t *Trie) Apply(fn func(*TrieNode)) {
 	}
 }
 
+// sortChildren sorts the children of the node by the code of their letter,
+// so FrozenTrie can binary search them. Letters not in the alphabet go last.
+func (t *Trie) sortChildren(node *TrieNode) {
+	sort.SliceStable(node.children, func(i, j int) bool {
+		ci, oki := t.GetAlphabet().encode(node.children[i].letter)
+		cj, okj := t.GetAlphabet().encode(node.children[j].letter)
+		if oki != okj {
+			return oki
+		}
+		return ci < cj
+	})
+}
+
 /**
   Encode the trie and all of its nodes. Returns a string representing the
-  encoded data.
+  encoded data. The children of every node are written in the order of the
+  alphabet.
 */
 func (t *Trie) Encode() string {
+	return t.encodeBits().GetData()
+}
+
+/**
+  Encode the trie and all of its nodes. Returns the encoded data packed into
+  64-bit words, for use with BinaryBitString.
+*/
+func (t *Trie) EncodeBinary() []uint64 {
+	return t.encodeBits().GetWords()
+}
+
+func (t *Trie) encodeBits() *BitWriter {
 	// Write the unary encoding of the tree in level order.
-	bits := BitWriter{}
+	bits := &BitWriter{}
 	bits.Write(0x02, 2)
 	t.Apply(func(node *TrieNode) {
 		for i := 0; i < len(node.children); i++ {
@@ -132,8 +263,10 @@ func (t *Trie) Encode() string {
 	// Write the data for each node, using (dataBits) bits for one node.
 	// 1 bit stores the "final" indicator. The other (dataBits-1) bits store
 	// one of the characters of the alphabet.
+	alphabet := t.GetAlphabet()
+	dataBits := alphabet.GetDataBits()
 	t.Apply(func(node *TrieNode) {
-		value, ok := mapCharToUint[node.letter]
+		value, ok := alphabet.encode(node.letter)
 		if !ok {
 			panic("illegal character:" + node.letter)
 		}
@@ -144,5 +277,5 @@ func (t *Trie) Encode() string {
 		bits.Write(uint(value), dataBits)
 	})
 
-	return bits.GetData()
+	return bits
 }
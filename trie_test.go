@@ -1,6 +1,9 @@
 package bits
 
-import "testing"
+import (
+	"bytes"
+	"testing"
+)
 
 func insertInAlphabeticalOrder(te *Trie) {
 	te.Insert("alphapha")
@@ -41,3 +44,34 @@ func TestTrie(t *testing.T) {
 	}
 	t.Log(rd.GetData())
 }
+
+func TestInsertIllegalCharacter(t *testing.T) {
+	te := Trie{}
+	te.Init()
+	if err := te.Insert("apple"); err != nil {
+		t.Error(err)
+	}
+	err := te.Insert("appLe")
+	ice, ok := err.(*IllegalCharacterError)
+	if !ok {
+		t.Fatal("expected *IllegalCharacterError, got ", err)
+	}
+	if ice.Word != "appLe" || ice.Rune != 'L' {
+		t.Error("wrong word or rune", ice.Word, ice.Rune)
+	}
+	if te.GetNodeCount() != 6 {
+		t.Error("trie changed by illegal word, node count ", te.GetNodeCount())
+	}
+}
+
+func TestInsertNoSpace(t *testing.T) {
+	te := Trie{}
+	te.InitWithAlphabet(CreateAlphabet("abc"))
+	if err := te.Insert("abc"); err != ErrNoSpace {
+		t.Error("Insert", err)
+	}
+	var buf bytes.Buffer
+	if err := te.Save(&buf); err != ErrNoSpace {
+		t.Error("Save", err)
+	}
+}
@@ -0,0 +1,77 @@
+package bits
+
+/**
+ * The trie data written as JSON by the examples and the succinct-trie
+ * command.
+ */
+
+/**
+  The encoded trie, its rank directory and node count, ready to be marshaled
+  with encoding/json. The alphabet is omitted by old files, which were
+  encoded with the alphabet of the program reading them. The normalizers are
+  omitted if there are none. SortedChildren is false in old files, whose
+  children are in the order they were inserted.
+*/
+type TrieData struct {
+	EncodedData       string
+	NodeCount         uint
+	RankDirectoryData string
+	Alphabet          string   `json:",omitempty"`
+	Normalizers       []string `json:",omitempty"`
+	SortedChildren    bool     `json:",omitempty"`
+}
+
+/**
+  Encode the trie and return its TrieData. The global L1 and L2 are used as
+  the sizes of the directory blocks.
+*/
+func CreateTrieData(t *Trie) TrieData {
+	data := t.Encode()
+	rd := CreateRankDirectory(data, t.GetNodeCount()*2+1, L1, L2)
+	return TrieData{
+		EncodedData:       data,
+		NodeCount:         t.GetNodeCount(),
+		RankDirectoryData: rd.GetData(),
+		Alphabet:          t.GetAlphabet().GetCharacters(),
+		Normalizers:       t.GetNormalizers(),
+		SortedChildren:    true,
+	}
+}
+
+/**
+  Returns the alphabet of the data, or the given alphabet if the data has
+  none.
+*/
+func (td *TrieData) GetAlphabet(alphabet *Alphabet) *Alphabet {
+	if td.Alphabet != "" {
+		return CreateAlphabet(td.Alphabet)
+	}
+	return alphabet
+}
+
+/**
+  Create the FrozenTrie of the data, see CreateFrozenTrie, with the
+  normalizers of the data. The given alphabet is used if the data has none.
+*/
+func (td *TrieData) CreateFrozenTrie(alphabet *Alphabet) (*FrozenTrie, error) {
+	alphabet = td.GetAlphabet(alphabet)
+	if err := checkTrieParams(td.NodeCount, L1, L2, alphabet); err != nil {
+		return nil, err
+	}
+	if uint(len(td.EncodedData))*W < td.NodeCount {
+		return nil, &DataError{Reason: nodeCountTooLarge}
+	}
+	bits, directory, reason := checkBase64Bits(td.EncodedData, td.RankDirectoryData,
+		td.NodeCount*2+1, td.NodeCount*2+1+td.NodeCount*alphabet.GetDataBits(), L1, L2)
+	if reason != "" {
+		return nil, &DataError{Reason: reason}
+	}
+	f, err := createFrozenTrie(bits, directory, td.NodeCount, L1, L2, alphabet, td.SortedChildren)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.SetNormalizers(td.Normalizers...); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
@@ -0,0 +1,52 @@
+package bits
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestTrieData(t *testing.T) {
+	alphabet := CreateAlphabet("abcdeghijklmnoprstuvyāīūṁṃŋṇṅñṭḍḷ…'’° -")
+	te := Trie{}
+	te.InitWithAlphabet(alphabet)
+	te.Insert("dhamma")
+	te.Insert("saṅgha")
+
+	b, err := json.Marshal(CreateTrieData(&te))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var td TrieData
+	if err := json.Unmarshal(b, &td); err != nil {
+		t.Fatal(err)
+	}
+	ft, err := td.CreateFrozenTrie(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ft.Lookup("saṅgha") || ft.GetAlphabet().GetCharacters() != alphabet.GetCharacters() {
+		t.Error("CreateFrozenTrie")
+	}
+
+	// the files of the examples have no alphabet
+	te2 := Trie{}
+	te2.Init()
+	te2.Insert("apple")
+	td2 := CreateTrieData(&te2)
+	b, _ = json.Marshal(map[string]interface{}{
+		"EncodedData":       td2.EncodedData,
+		"NodeCount":         td2.NodeCount,
+		"RankDirectoryData": td2.RankDirectoryData,
+	})
+	var td3 TrieData
+	if err := json.Unmarshal(b, &td3); err != nil {
+		t.Fatal(err)
+	}
+	ft2, err := td3.CreateFrozenTrie(DefaultAlphabet())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ft2.Lookup("apple") {
+		t.Error("CreateFrozenTrie without alphabet")
+	}
+}
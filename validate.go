@@ -0,0 +1,175 @@
+package bits
+
+/**
+ * Validation of encoded tries. FrozenTrie.Init trusts its input and panics
+ * while traversing bad data, the constructors here check the data first and
+ * return an error instead.
+ */
+
+/**
+  Create a FrozenTrie from the encoded data, after checking that the data, the
+  rank directory and the node count describe a valid trie of the given
+  alphabet. The global L1 and L2 are used as the sizes of the directory
+  blocks. A *DataError is returned if the data is not valid.
+*/
+func CreateFrozenTrie(data, directoryData string, nodeCount uint, alphabet *Alphabet) (*FrozenTrie, error) {
+	if err := checkTrieParams(nodeCount, L1, L2, alphabet); err != nil {
+		return nil, err
+	}
+	if uint(len(data))*W < nodeCount {
+		return nil, &DataError{Reason: nodeCountTooLarge}
+	}
+	bits, directory, reason := checkBase64Bits(data, directoryData,
+		nodeCount*2+1, nodeCount*2+1+nodeCount*alphabet.GetDataBits(), L1, L2)
+	if reason != "" {
+		return nil, &DataError{Reason: reason}
+	}
+	return createFrozenTrie(bits, directory, nodeCount, L1, L2, alphabet, false)
+}
+
+/**
+  Same as CreateFrozenTrie, but for the data and directory stored in 64-bit
+  words.
+*/
+func CreateBinaryFrozenTrie(data, directoryData []uint64, nodeCount uint, alphabet *Alphabet) (*FrozenTrie, error) {
+	if err := checkTrieParams(nodeCount, L1, L2, alphabet); err != nil {
+		return nil, err
+	}
+	if uint(len(data))*64 < nodeCount {
+		return nil, &DataError{Reason: nodeCountTooLarge}
+	}
+	bits, directory, reason := checkBinaryBits(data, directoryData,
+		nodeCount*2+1, nodeCount*2+1+nodeCount*alphabet.GetDataBits(), L1, L2)
+	if reason != "" {
+		return nil, &DataError{Reason: reason}
+	}
+	return createFrozenTrie(bits, directory, nodeCount, L1, L2, alphabet, false)
+}
+
+// createFrozenTrie creates and validates the FrozenTrie. If sorted is true,
+// the children of every node must be in the order of the alphabet.
+func createFrozenTrie(data, directory BitVector, nodeCount, l1Size, l2Size uint, alphabet *Alphabet, sorted bool) (*FrozenTrie, error) {
+	f := &FrozenTrie{}
+	f.init(data, directory, nodeCount, l1Size, l2Size, alphabet)
+	f.sortedChildren = sorted
+	if err := f.validate(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// nodeCountTooLarge is the reason given for node counts larger than the
+// number of bits of the data. The lengths computed from such counts could
+// overflow and pass the length checks.
+const nodeCountTooLarge = "node count larger than the data"
+
+func checkTrieParams(nodeCount, l1Size, l2Size uint, alphabet *Alphabet) error {
+	if alphabet == nil {
+		return &DataError{Reason: "no alphabet"}
+	}
+	if nodeCount == 0 {
+		return &DataError{Reason: "no nodes"}
+	}
+	if l2Size == 0 || l1Size < l2Size || l1Size%l2Size != 0 {
+		return &DataError{Reason: "bad directory block sizes"}
+	}
+	return nil
+}
+
+// checkBase64Bits makes sure the data has the expected length and the
+// directory is the one of the data. It returns the reason if not.
+func checkBase64Bits(data, directoryData string, numBits, totalBits, l1Size, l2Size uint) (BitVector, BitVector, string) {
+	if uint(len(data)) != (totalBits+W-1)/W {
+		return nil, nil, "data length does not match node count"
+	}
+	for i := 0; i < len(data); i++ {
+		if BASE64[ORD(data[i:i+1])] != data[i] {
+			return nil, nil, "illegal BASE-64 character"
+		}
+	}
+
+	plain := CreateRankDirectory(data, numBits, l1Size, l2Size)
+	if len(directoryData) < len(plain.GetData()) {
+		return nil, nil, "rank directory too short"
+	}
+	stored := RankDirectory{}
+	stored.Init(directoryData, data, numBits, l1Size, l2Size)
+	rd := CreateRankDirectoryWithSelect(data, numBits, l1Size, l2Size, stored.GetSelectSampleRate())
+	if rd.GetData() != directoryData {
+		return nil, nil, "rank directory does not match data"
+	}
+	return rd.data, rd.directory, ""
+}
+
+// checkBinaryBits is the same as checkBase64Bits for bits stored in 64-bit
+// words.
+func checkBinaryBits(data, directoryData []uint64, numBits, totalBits, l1Size, l2Size uint) (BitVector, BitVector, string) {
+	if uint(len(data)) != (totalBits+63)/64 {
+		return nil, nil, "data length does not match node count"
+	}
+
+	plain := CreateBinaryRankDirectory(data, numBits, l1Size, l2Size)
+	if len(directoryData) < len(plain.GetWords()) {
+		return nil, nil, "rank directory too short"
+	}
+	stored := RankDirectory{}
+	stored.InitBinary(directoryData, data, numBits, l1Size, l2Size)
+	rd := CreateBinaryRankDirectoryWithSelect(data, numBits, l1Size, l2Size, stored.GetSelectSampleRate())
+	expected := rd.GetWords()
+	if len(expected) != len(directoryData) {
+		return nil, nil, "rank directory does not match data"
+	}
+	for i := range expected {
+		if expected[i] != directoryData[i] {
+			return nil, nil, "rank directory does not match data"
+		}
+	}
+	return rd.data, rd.directory, ""
+}
+
+// validate makes sure the unary encoding is a tree in level order and every
+// letter is in the alphabet, so that traversing the trie cannot fail. If the
+// trie says its children are sorted, they must be.
+func (f *FrozenTrie) validate() error {
+	if f.data.Get(0, 2) != 0x02 {
+		return &DataError{Reason: "bad root encoding"}
+	}
+
+	// Every 1 bit is a node whose parent is the node the last 0 bit
+	// belongs to, every 0 bit ends the children of the next node.
+	var ones, zeros uint = 1, 1
+	first := true
+	var p uint
+	for p = 2; p < f.letterStart; p++ {
+		if f.data.Get(p, 1) == 1 {
+			if zeros > ones {
+				return &DataError{Reason: "child before its parent"}
+			}
+			if f.sortedChildren && !first && ones < f.nodeCount &&
+				f.getCode(ones-1) >= f.getCode(ones) {
+				return &DataError{Reason: "children not in alphabet order"}
+			}
+			first = false
+			ones++
+		} else {
+			first = true
+			zeros++
+			if zeros-1 > ones {
+				return &DataError{Reason: "children of a missing node"}
+			}
+		}
+	}
+	if ones != f.nodeCount || zeros != f.nodeCount+1 {
+		return &DataError{Reason: "unary encoding does not match node count"}
+	}
+
+	dataBits := f.alphabet.GetDataBits()
+	var index uint
+	for index = 0; index < f.nodeCount; index++ {
+		code := f.data.Get(f.letterStart+index*dataBits+1, dataBits-1)
+		if _, ok := f.alphabet.decode(code); !ok {
+			return &DataError{Reason: "letter not in alphabet"}
+		}
+	}
+	return nil
+}
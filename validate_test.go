@@ -0,0 +1,81 @@
+package bits
+
+import "testing"
+
+func TestCreateFrozenTrie(t *testing.T) {
+	te := Trie{}
+	te.Init()
+	insertNotInAlphabeticalOrder(&te)
+	teData := te.Encode()
+	rd := CreateRankDirectory(teData, te.GetNodeCount()*2+1, L1, L2)
+
+	ft, err := CreateFrozenTrie(teData, rd.GetData(), te.GetNodeCount(), DefaultAlphabet())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ft.Lookup("lamp") != true {
+		t.Error("lamp")
+	}
+
+	words := te.EncodeBinary()
+	brd := CreateBinaryRankDirectory(words, te.GetNodeCount()*2+1, L1, L2)
+	if _, err := CreateBinaryFrozenTrie(words, brd.GetWords(), te.GetNodeCount(), DefaultAlphabet()); err != nil {
+		t.Error(err)
+	}
+
+	// wrong node count
+	if _, err := CreateFrozenTrie(teData, rd.GetData(), te.GetNodeCount()-1, DefaultAlphabet()); err == nil {
+		t.Error("wrong node count accepted")
+	} else if _, ok := err.(*DataError); !ok {
+		t.Error("expected *DataError, got ", err)
+	}
+
+	// a letter which is not in the alphabet
+	bw := BitWriter{}
+	bw.Write(0x02, 2)
+	bw.Write(0x02, 2)
+	bw.Write(0, 1)
+	bw.Write(0, 6)
+	bw.Write(0x3f, 6)
+	rd = CreateRankDirectory(bw.GetData(), 5, L1, L2)
+	if _, err := CreateFrozenTrie(bw.GetData(), rd.GetData(), 2, DefaultAlphabet()); err == nil {
+		t.Error("illegal letter accepted")
+	}
+
+	// the unary encoding is not a tree
+	bw = BitWriter{}
+	bw.Write(0x02, 2)
+	bw.Write(0, 1)
+	bw.Write(0x02, 2)
+	bw.Write(0, 6)
+	bw.Write(0, 6)
+	rd = CreateRankDirectory(bw.GetData(), 5, L1, L2)
+	if _, err := CreateFrozenTrie(bw.GetData(), rd.GetData(), 2, DefaultAlphabet()); err == nil {
+		t.Error("bad unary encoding accepted")
+	}
+
+	// the directory of other data
+	other := Trie{}
+	other.Init()
+	insertInAlphabeticalOrder(&other)
+	otherData := other.Encode()
+	ord := CreateRankDirectory(otherData, other.GetNodeCount()*2+1, L1, L2)
+	if _, err := CreateFrozenTrie(teData, ord.GetData()+"A", te.GetNodeCount(), DefaultAlphabet()); err == nil {
+		t.Error("mismatched directory accepted")
+	}
+	// a node count for which the length of the data overflows to the
+	// length of teData
+	huge := ^uint(0)/8 + 1 + te.GetNodeCount()
+	if _, err := CreateFrozenTrie(teData, rd.GetData(), huge, DefaultAlphabet()); err == nil {
+		t.Error("huge node count accepted")
+	} else if _, ok := err.(*DataError); !ok {
+		t.Error("expected *DataError, got ", err)
+	}
+	if _, err := CreateBinaryFrozenTrie(words, brd.GetWords(), huge, DefaultAlphabet()); err == nil {
+		t.Error("huge node count accepted")
+	}
+	td := TrieData{EncodedData: teData, NodeCount: huge, RankDirectoryData: rd.GetData()}
+	if _, err := td.CreateFrozenTrie(DefaultAlphabet()); err == nil {
+		t.Error("huge node count accepted")
+	}
+}
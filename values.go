@@ -0,0 +1,63 @@
+package bits
+
+/**
+ * Values attached to words. The values are kept in a packed array indexed by
+ * word ID (see wordid.go), each value taking as many bits as the largest one.
+ */
+
+/**
+  Inserts a word into the trie with a value attached to it, which is returned
+  by FrozenTrie.Get. Inserting the word again replaces the value. Words
+  inserted by Insert have the value 0.
+*/
+func (t *Trie) InsertWithValue(word string, value uint64) error {
+	if err := t.Insert(word); err != nil {
+		return err
+	}
+	// the last node of the word is at the end of the cache
+	t.cache[len(t.cache)-1].value = value
+	t.hasValues = true
+	return nil
+}
+
+// writeValues writes the values of the words, in the order of word IDs.
+func (t *Trie) writeValues() (*BitWriter, uint) {
+	var values []uint64
+	t.Apply(func(node *TrieNode) {
+		if node.final {
+			values = append(values, node.value)
+		}
+	})
+	return writePackedArray(values)
+}
+
+/**
+  Encode the values attached to the words. Returns a string representing the
+  encoded values and the number of bits of each value, to be passed to
+  FrozenTrie.SetValues.
+*/
+func (t *Trie) EncodeValues() (string, uint) {
+	bw, width := t.writeValues()
+	return bw.GetData(), width
+}
+
+/**
+  Set the values of the words, as encoded by Trie.EncodeValues.
+*/
+func (f *FrozenTrie) SetValues(data string, width uint) {
+	bits := &BitString{}
+	bits.Init(data)
+	f.values.init(bits, width, f.GetWordCount())
+}
+
+/**
+  Returns the value attached to the word, and true if the word is in the
+  trie. The value is 0 if the trie has no values.
+*/
+func (f *FrozenTrie) Get(word string) (uint64, bool) {
+	id, ok := f.Index(word)
+	if !ok {
+		return 0, false
+	}
+	return f.values.get(id), true
+}
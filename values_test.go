@@ -0,0 +1,62 @@
+package bits
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestValues(t *testing.T) {
+	te := Trie{}
+	te.Init()
+	te.InsertWithValue("apple", 3)
+	te.InsertWithValue("orange", 1<<40)
+	te.Insert("lamp")
+	te.InsertWithValue("hello", 17)
+	te.InsertWithValue("apple", 5)
+
+	teData := te.Encode()
+	rd := CreateRankDirectory(teData, te.GetNodeCount()*2+1, L1, L2)
+	ft := FrozenTrie{}
+	ft.Init(teData, rd.GetData(), te.GetNodeCount())
+
+	if _, ok := ft.Get("hello"); !ok {
+		t.Error("hello")
+	}
+	if v, _ := ft.Get("hello"); v != 0 {
+		t.Error("value without SetValues", v)
+	}
+
+	values, width := te.EncodeValues()
+	if width != 41 {
+		t.Error("Expected width 41, got ", width)
+	}
+	ft.SetValues(values, width)
+
+	var buf bytes.Buffer
+	te.SetStorage(StorageBinary)
+	if err := te.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, f := range []*FrozenTrie{&ft, loaded} {
+		if v, ok := f.Get("apple"); !ok || v != 5 {
+			t.Error("apple", v, ok)
+		}
+		if v, ok := f.Get("orange"); !ok || v != 1<<40 {
+			t.Error("orange", v, ok)
+		}
+		if v, ok := f.Get("lamp"); !ok || v != 0 {
+			t.Error("lamp", v, ok)
+		}
+		if v, ok := f.Get("hello"); !ok || v != 17 {
+			t.Error("hello", v, ok)
+		}
+		if _, ok := f.Get("hell"); ok {
+			t.Error("hell")
+		}
+	}
+}
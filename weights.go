@@ -0,0 +1,195 @@
+package bits
+
+/**
+ * Weighted autocomplete. Every word has a weight, for example its frequency,
+ * kept in a packed array indexed by word ID. Every node also has the maximum
+ * weight of the words in its subtrie, kept in a packed array indexed by node,
+ * so the search for the heaviest completions of a prefix can always expand
+ * the most promising node first and never visits branches which cannot beat
+ * the words already found.
+ */
+
+import "container/heap"
+
+/**
+  Inserts a word into the trie with a weight, which ranks the word in
+  FrozenTrie.GetTopSuggestedWords. Inserting the word again replaces the
+  weight. Words inserted by Insert have the weight 0.
+*/
+func (t *Trie) InsertWithWeight(word string, weight uint64) error {
+	if err := t.Insert(word); err != nil {
+		return err
+	}
+	// the last node of the word is at the end of the cache
+	t.cache[len(t.cache)-1].weight = weight
+	t.hasWeights = true
+	return nil
+}
+
+// writeWeights writes the weights of the words, in the order of word IDs, and
+// the maximum weight in the subtrie of every node, in level order. Both use
+// the same width.
+func (t *Trie) writeWeights() (*BitWriter, *BitWriter, uint) {
+	var nodes []*TrieNode
+	var weights []uint64
+	t.Apply(func(node *TrieNode) {
+		nodes = append(nodes, node)
+		if node.final {
+			weights = append(weights, node.weight)
+		}
+	})
+
+	// children come after their parent in level order
+	maxWeights := make([]uint64, len(nodes))
+	maxOfNode := make(map[*TrieNode]uint64, len(nodes))
+	for i := len(nodes) - 1; i >= 0; i-- {
+		var max uint64 = 0
+		if nodes[i].final {
+			max = nodes[i].weight
+		}
+		for _, child := range nodes[i].children {
+			if maxOfNode[child] > max {
+				max = maxOfNode[child]
+			}
+		}
+		maxOfNode[nodes[i]] = max
+		maxWeights[i] = max
+	}
+
+	// the root has the largest weight, so its width fits all the others
+	maxBits, width := writePackedArray(maxWeights)
+	weightBits := &BitWriter{}
+	for _, weight := range weights {
+		writeUint64(weightBits, weight, width)
+	}
+	return weightBits, maxBits, width
+}
+
+/**
+  Encode the weights of the words. Returns the encoded weights, the encoded
+  maximum weights of the subtries and the number of bits of each weight, to
+  be passed to FrozenTrie.SetWeights.
+*/
+func (t *Trie) EncodeWeights() (string, string, uint) {
+	weights, maxWeights, width := t.writeWeights()
+	return weights.GetData(), maxWeights.GetData(), width
+}
+
+/**
+  Set the weights of the words, as encoded by Trie.EncodeWeights.
+*/
+func (f *FrozenTrie) SetWeights(weights, maxWeights string, width uint) {
+	wbits := &BitString{}
+	wbits.Init(weights)
+	mbits := &BitString{}
+	mbits.Init(maxWeights)
+	f.weights.init(wbits, width, f.GetWordCount())
+	f.maxWeights.init(mbits, width, f.nodeCount)
+}
+
+/**
+  Returns the weight of the word, and true if the word is in the trie.
+*/
+func (f *FrozenTrie) GetWeight(word string) (uint64, bool) {
+	id, ok := f.Index(word)
+	if !ok {
+		return 0, false
+	}
+	return f.weights.get(id), true
+}
+
+/**
+  Returns at most k words which start with prefix, the heaviest first. Words
+  of the same weight are returned in level order. The prefix is normalized
+  first if the trie has normalizers.
+*/
+func (f *FrozenTrie) GetTopSuggestedWords(prefix string, k int) []string {
+	var result []string
+
+	prefix = f.normalizeWord(prefix)
+	node, ok := f.findNode(prefix)
+	if !ok || k <= 0 {
+		return result
+	}
+
+	pq := &weightQueue{}
+	pq.push(weightItem{index: node.index, word: prefix, weight: f.maxWeights.get(node.index)})
+
+	for pq.Len() > 0 {
+		item := heap.Pop(pq).(weightItem)
+		if item.isWord {
+			result = append(result, item.word)
+			if len(result) == k {
+				return result
+			}
+			continue
+		}
+
+		node := f.GetNodeByIndex(item.index)
+		if node.final {
+			pq.push(weightItem{
+				index:  node.index,
+				word:   item.word,
+				weight: f.weights.get(f.wordID(node.index)),
+				isWord: true,
+			})
+		}
+		var i uint = 0
+		for ; i < node.GetChildCount(); i++ {
+			child := node.firstChild + i
+			pq.push(weightItem{
+				index:  child,
+				word:   item.word + f.getLetter(child),
+				weight: f.maxWeights.get(child),
+			})
+		}
+	}
+
+	return result
+}
+
+// weightItem is a node to expand, with the maximum weight of its subtrie,
+// or a word found, with its weight.
+type weightItem struct {
+	index  uint
+	word   string
+	weight uint64
+	isWord bool
+	seq    int
+}
+
+// weightQueue is a priority queue of weightItem, the heaviest first. For
+// the same weight, words come before nodes, then the item pushed first.
+type weightQueue struct {
+	items []weightItem
+	seq   int
+}
+
+func (q *weightQueue) push(item weightItem) {
+	item.seq = q.seq
+	q.seq++
+	heap.Push(q, item)
+}
+
+func (q *weightQueue) Len() int { return len(q.items) }
+
+func (q *weightQueue) Less(i, j int) bool {
+	a, b := q.items[i], q.items[j]
+	if a.weight != b.weight {
+		return a.weight > b.weight
+	}
+	if a.isWord != b.isWord {
+		return a.isWord
+	}
+	return a.seq < b.seq
+}
+
+func (q *weightQueue) Swap(i, j int) { q.items[i], q.items[j] = q.items[j], q.items[i] }
+
+func (q *weightQueue) Push(x interface{}) { q.items = append(q.items, x.(weightItem)) }
+
+func (q *weightQueue) Pop() interface{} {
+	item := q.items[len(q.items)-1]
+	q.items = q.items[:len(q.items)-1]
+	return item
+}
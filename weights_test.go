@@ -0,0 +1,53 @@
+package bits
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestTopSuggestedWords(t *testing.T) {
+	te := Trie{}
+	te.Init()
+	te.InsertWithWeight("a", 1)
+	te.InsertWithWeight("ab", 2)
+	te.InsertWithWeight("abandon", 50)
+	te.InsertWithWeight("able", 70)
+	te.InsertWithWeight("about", 900)
+	te.InsertWithWeight("above", 300)
+	te.InsertWithWeight("zebra", 1000)
+	te.Insert("abc")
+
+	var buf bytes.Buffer
+	if err := te.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	teData := te.Encode()
+	rd := CreateRankDirectory(teData, te.GetNodeCount()*2+1, L1, L2)
+	ft := FrozenTrie{}
+	ft.Init(teData, rd.GetData(), te.GetNodeCount())
+	ft.SetWeights(te.EncodeWeights())
+
+	for _, f := range []*FrozenTrie{&ft, loaded} {
+		if !reflect.DeepEqual(f.GetTopSuggestedWords("a", 3), []string{"about", "above", "able"}) {
+			t.Error(`GetTopSuggestedWords("a", 3)`, f.GetTopSuggestedWords("a", 3))
+		}
+		if !reflect.DeepEqual(f.GetTopSuggestedWords("ab", 10), []string{"about", "above", "able", "abandon", "ab", "abc"}) {
+			t.Error(`GetTopSuggestedWords("ab", 10)`, f.GetTopSuggestedWords("ab", 10))
+		}
+		if !reflect.DeepEqual(f.GetTopSuggestedWords("", 1), []string{"zebra"}) {
+			t.Error(`GetTopSuggestedWords("", 1)`, f.GetTopSuggestedWords("", 1))
+		}
+		if len(f.GetTopSuggestedWords("x", 10)) != 0 {
+			t.Error(`GetTopSuggestedWords("x", 10)`)
+		}
+		if w, ok := f.GetWeight("above"); !ok || w != 300 {
+			t.Error("GetWeight", w, ok)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package bits
+
+/**
+ * Wildcard queries. The pattern is run as a small automaton whose states are
+ * the positions in the pattern: "?" moves to the next position on any letter
+ * and "*" stays on its position or moves on without a letter. The trie is
+ * traversed depth-first in the order of the alphabet, and a branch is pruned
+ * as soon as no position of the pattern is left.
+ */
+
+/**
+  Returns at most limit words of the trie matching the pattern, in the order
+  of the alphabet. In the pattern, "?" matches any single letter and "*"
+  matches any sequence of letters, including none. The pattern is not
+  normalized: if the trie has normalizers, it must already be in the normal
+  form of the words.
+*/
+func (f *FrozenTrie) Match(pattern string, limit int) []string {
+	var result []string
+	if limit <= 0 {
+		return result
+	}
+
+	var tokens []string
+	for _, runeValue := range pattern {
+		tokens = append(tokens, string(runeValue))
+	}
+
+	wm := wildcardMatch{trie: f, tokens: tokens, limit: limit}
+	start := make([]bool, len(tokens)+1)
+	start[0] = true
+	wm.visit(0, "", wm.closure(start))
+	return append(result, wm.result...)
+}
+
+type wildcardMatch struct {
+	trie   *FrozenTrie
+	tokens []string
+	limit  int
+	result []string
+}
+
+// closure adds the positions reached by skipping "*".
+func (wm *wildcardMatch) closure(states []bool) []bool {
+	for i, token := range wm.tokens {
+		if states[i] && token == "*" {
+			states[i+1] = true
+		}
+	}
+	return states
+}
+
+// step returns the positions reached from states by reading the letter, and
+// false if there are none.
+func (wm *wildcardMatch) step(states []bool, letter string) ([]bool, bool) {
+	next := make([]bool, len(states))
+	any := false
+	for i, token := range wm.tokens {
+		if !states[i] {
+			continue
+		}
+		switch token {
+		case "*":
+			next[i] = true
+			any = true
+		case "?", letter:
+			next[i+1] = true
+			any = true
+		}
+	}
+	return wm.closure(next), any
+}
+
+// visit returns false when enough words are found.
+func (wm *wildcardMatch) visit(index uint, prefix string, states []bool) bool {
+	if states[len(wm.tokens)] && wm.trie.isFinal(index) {
+		wm.result = append(wm.result, prefix)
+		if len(wm.result) == wm.limit {
+			return false
+		}
+	}
+
+	children := wm.trie.getSortedChildren(index)
+	var i uint
+	for i = 0; i < children.childCount; i++ {
+		child := children.get(i)
+		letter := wm.trie.getLetter(child)
+		next, ok := wm.step(states, letter)
+		if !ok {
+			continue
+		}
+		if !wm.visit(child, prefix+letter, next) {
+			return false
+		}
+	}
+	return true
+}
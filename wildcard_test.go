@@ -0,0 +1,41 @@
+package bits
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	te := Trie{}
+	te.InitWithAlphabet(CreateAlphabet("abcdeghijklmnoprstuvyāīūṁṃŋṇṅñṭḍḷ…'’° -"))
+	te.Insert("sacca")
+	te.Insert("saccavācā")
+	te.Insert("dhamma")
+	te.Insert("dhammaṃ")
+	te.Insert("damma")
+	te.Insert("buddho")
+	teData := te.Encode()
+	rd := CreateRankDirectory(teData, te.GetNodeCount()*2+1, L1, L2)
+	ft := FrozenTrie{}
+	ft.InitWithAlphabet(teData, rd.GetData(), te.GetNodeCount(), te.GetAlphabet())
+
+	cases := []struct {
+		pattern  string
+		limit    int
+		expected []string
+	}{
+		{"d?amma", 10, []string{"dhamma"}},
+		{"sacc*", 10, []string{"sacca", "saccavācā"}},
+		{"*mma*", 10, []string{"damma", "dhamma", "dhammaṃ"}},
+		{"*a", 10, []string{"damma", "dhamma", "sacca"}},
+		{"*", 2, []string{"buddho", "damma"}},
+		{"?", 10, nil},
+		{"buddho", 10, []string{"buddho"}},
+		{"b*x", 10, nil},
+	}
+	for _, c := range cases {
+		if !reflect.DeepEqual(ft.Match(c.pattern, c.limit), c.expected) {
+			t.Error("Match", c.pattern, ft.Match(c.pattern, c.limit))
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package bits
+
+/**
+ * Mapping between words and dense word IDs. The ID of a word is the number
+ * of final nodes before its last node in level order, so the IDs of the words
+ * of a trie are 0, 1, ..., GetWordCount()-1. A rank directory over the
+ * "final" indicators of the nodes gives the ID of a node, and select gives
+ * the node of an ID. The word itself is spelled by following the parents up
+ * to the root.
+ */
+
+import (
+	"errors"
+	"strings"
+	"sync"
+)
+
+// ErrWordIDOutOfRange is returned by FrozenTrie.Word for unknown IDs.
+var ErrWordIDOutOfRange = errors.New("bits: word ID out of range")
+
+// the sample rate of the select samples of the "final" indicators
+const finalsSelectSampleRate = 32
+
+// lazyFinals is the rank directory of the "final" indicators. It takes a
+// scan over all the nodes to build, so it is built by the first call which
+// needs word IDs rather than by Init.
+type lazyFinals struct {
+	once      sync.Once
+	directory RankDirectory
+}
+
+// getFinals returns the rank directory of the "final" indicators, and builds
+// it if this is the first call.
+func (f *FrozenTrie) getFinals() *RankDirectory {
+	f.finals.once.Do(f.initFinals)
+	return &f.finals.directory
+}
+
+func (f *FrozenTrie) initFinals() {
+	bw := BitWriter{}
+	var index uint
+	for index = 0; index < f.nodeCount; index++ {
+		if f.isFinal(index) {
+			bw.Write(1, 1)
+		} else {
+			bw.Write(0, 1)
+		}
+	}
+	f.finals.directory = CreateBinaryRankDirectoryWithSelect(bw.GetWords(), f.nodeCount, L1, L2, finalsSelectSampleRate)
+}
+
+// isFinal returns the "final" indicator of the node.
+func (f *FrozenTrie) isFinal(index uint) bool {
+	return f.data.Get(f.letterStart+index*f.alphabet.GetDataBits(), 1) == 1
+}
+
+// getCode returns the code of the letter of the node in the alphabet.
+func (f *FrozenTrie) getCode(index uint) uint {
+	dataBits := f.alphabet.GetDataBits()
+	return f.data.Get(f.letterStart+index*dataBits+1, dataBits-1)
+}
+
+// getLetter returns the letter of the node. Unlike GetNodeByIndex, it does
+// not look up the children.
+func (f *FrozenTrie) getLetter(index uint) string {
+	letter, ok := f.alphabet.decode(f.getCode(index))
+	if !ok {
+		panic("illegal: bits -> char failed")
+	}
+	return letter
+}
+
+// getParent returns the index of the parent of a node which is not the root.
+// The node is the (index+1)th 1 bit of the unary encoding, and its parent is
+// the node whose children end with the next 0 bit.
+func (f *FrozenTrie) getParent(index uint) uint {
+	return f.directory.Rank(0, f.directory.Select(1, index+1)) - 1
+}
+
+/**
+  Returns the number of words in the trie.
+*/
+func (f *FrozenTrie) GetWordCount() uint {
+	if f.nodeCount == 0 {
+		return 0
+	}
+	return f.getFinals().Rank(1, f.nodeCount-1)
+}
+
+/**
+  Returns the ID of the word, in [0, GetWordCount()), and true if the word is
+  in the trie. The word is normalized first if the trie has normalizers.
+*/
+func (f *FrozenTrie) Index(word string) (uint, bool) {
+	node, ok := f.findNode(f.normalizeWord(word))
+	if !ok || !node.final {
+		return 0, false
+	}
+	return f.wordID(node.index), true
+}
+
+// wordID returns the ID of the word ending at the given final node.
+func (f *FrozenTrie) wordID(index uint) uint {
+	return f.getFinals().Rank(1, index) - 1
+}
+
+// wordNode returns the index of the final node of the word with the given ID.
+func (f *FrozenTrie) wordNode(id uint) uint {
+	return f.getFinals().Select(1, id+1)
+}
+
+/**
+  Returns the word with the given ID. It is the inverse of Index.
+*/
+func (f *FrozenTrie) Word(id uint) (string, error) {
+	if id >= f.GetWordCount() {
+		return "", ErrWordIDOutOfRange
+	}
+	return f.spell(f.wordNode(id)), nil
+}
+
+// spell returns the letters on the path from the root to the node.
+func (f *FrozenTrie) spell(index uint) string {
+	var letters []string
+	for ; index != 0; index = f.getParent(index) {
+		letters = append(letters, f.getLetter(index))
+	}
+
+	for i, j := 0, len(letters)-1; i < j; i, j = i+1, j-1 {
+		letters[i], letters[j] = letters[j], letters[i]
+	}
+	return strings.Join(letters, "")
+}
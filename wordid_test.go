@@ -0,0 +1,74 @@
+package bits
+
+import "testing"
+
+func TestWordID(t *testing.T) {
+	te := Trie{}
+	te.Init()
+	insertNotInAlphabeticalOrder(&te)
+	teData := te.Encode()
+	rd := CreateRankDirectory(teData, te.GetNodeCount()*2+1, L1, L2)
+
+	ft := FrozenTrie{}
+	ft.Init(teData, rd.GetData(), te.GetNodeCount())
+
+	if ft.GetWordCount() != 7 {
+		t.Error("Expected 7 words, got ", ft.GetWordCount())
+	}
+
+	seen := map[uint]bool{}
+	for _, word := range []string{"apple", "orange", "alphapha", "lamp", "hello", "jello", "quiz"} {
+		id, ok := ft.Index(word)
+		if !ok {
+			t.Error("Index", word)
+			continue
+		}
+		if id >= ft.GetWordCount() || seen[id] {
+			t.Error("bad ID", word, id)
+		}
+		seen[id] = true
+
+		w, err := ft.Word(id)
+		if err != nil || w != word {
+			t.Error("Word", id, w, err)
+		}
+	}
+
+	if _, ok := ft.Index("appl"); ok {
+		t.Error("appl")
+	}
+	if _, ok := ft.Index("apples"); ok {
+		t.Error("apples")
+	}
+	if _, err := ft.Word(7); err != ErrWordIDOutOfRange {
+		t.Error("expected ErrWordIDOutOfRange, got ", err)
+	}
+}
+
+func TestWordIDConcurrent(t *testing.T) {
+	te := Trie{}
+	te.Init()
+	insertNotInAlphabeticalOrder(&te)
+	teData := te.Encode()
+	rd := CreateRankDirectory(teData, te.GetNodeCount()*2+1, L1, L2)
+
+	// the directory of the "final" indicators is built by the first of
+	// the calls
+	ft := FrozenTrie{}
+	ft.Init(teData, rd.GetData(), te.GetNodeCount())
+
+	words := []string{"apple", "orange", "alphapha", "lamp", "hello", "jello", "quiz"}
+	done := make(chan bool)
+	for _, word := range words {
+		go func(word string) {
+			id, ok := ft.Index(word)
+			w, err := ft.Word(id)
+			done <- ok && err == nil && w == word
+		}(word)
+	}
+	for range words {
+		if !<-done {
+			t.Error("Index and Word do not match")
+		}
+	}
+}